@@ -0,0 +1,167 @@
+// Package animation records the steps of a simulation as frames and writes
+// them as an animated GIF.
+//
+// Importing the package registers the flags
+//
+//	-record      write an animated GIF of the simulation to this file
+//	-fps         frames per second of the animation
+//	-cell-size   size of one grid cell in pixels
+//	-max-frames  upper bound of frames kept in memory
+//
+// Days get their recorder with FromFlags after flag.Parse. The recorder is
+// nil when nothing is recorded and all methods accept a nil receiver, so the
+// simulation loops can emit frames unconditionally.
+package animation
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+
+	"aoc/render"
+)
+
+var (
+	output    = flag.String("record", "", "write an animated GIF of the simulation to this file")
+	fps       = flag.Int("fps", 10, "frames per second of the recorded animation")
+	cellSize  = flag.Int("cell-size", 4, "size of one grid cell in pixels in the recorded animation")
+	maxFrames = flag.Int("max-frames", 500, "maximum number of frames kept, longer simulations are thinned out")
+)
+
+type Recorder struct {
+	FPS      int
+	CellSize int
+	// MaxFrames bounds the memory used. Once it is reached every other frame
+	// is dropped and only every second frame is kept from then on, so the
+	// animation always covers the whole simulation.
+	MaxFrames int
+
+	path   string
+	every  int
+	count  int
+	frames []*image.Paletted
+}
+
+func New(fps, cellSize int) *Recorder {
+	return &Recorder{FPS: fps, CellSize: cellSize, MaxFrames: 500}
+}
+
+// FromFlags returns a recorder set up by the command line flags, or nil if
+// -record was not given.
+func FromFlags() *Recorder {
+	if *output == "" {
+		return nil
+	}
+	r := New(*fps, *cellSize)
+	r.MaxFrames = *maxFrames
+	r.path = *output
+	return r
+}
+
+func (r *Recorder) Enabled() bool {
+	return r != nil
+}
+
+// Frame adds a frame. The picture is only drawn if the frame is kept, so
+// emitting a frame for every step is cheap when the recorder is disabled or
+// thinning out frames.
+func (r *Recorder) Frame(picture func() *render.Picture) {
+	if r == nil {
+		return
+	}
+	if r.every == 0 {
+		r.every = 1
+	}
+	r.count++
+	if (r.count-1)%r.every != 0 {
+		return
+	}
+	if r.MaxFrames > 1 && len(r.frames) >= r.MaxFrames {
+		kept := r.frames[:0]
+		for i := 0; i < len(r.frames); i += 2 {
+			kept = append(kept, r.frames[i])
+		}
+		r.frames = kept
+		r.every *= 2
+		if (r.count-1)%r.every != 0 {
+			return
+		}
+	}
+	r.frames = append(r.frames, paletted(picture().Image(r.CellSize)))
+}
+
+func (r *Recorder) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.frames)
+}
+
+// paletted converts an image to the palette of its colours, falling back to
+// the nearest Plan 9 colour when there are more than a GIF can hold.
+func paletted(img *image.RGBA) *image.Paletted {
+	colors := color.Palette{}
+	seen := map[color.RGBA]bool{}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y && colors != nil; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if seen[c] {
+				continue
+			}
+			if len(colors) == 256 {
+				colors = nil
+				break
+			}
+			seen[c] = true
+			colors = append(colors, c)
+		}
+	}
+	if colors == nil {
+		colors = palette.Plan9
+	}
+	frame := image.NewPaletted(bounds, colors)
+	draw.Draw(frame, bounds, img, bounds.Min, draw.Src)
+	return frame
+}
+
+// Encode writes the recorded frames as a looping GIF. The last frame is
+// shown for a second longer.
+func (r *Recorder) Encode(w io.Writer) error {
+	anim := &gif.GIF{}
+	delay := 100 / max(r.FPS, 1)
+	for _, frame := range r.frames {
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	if len(anim.Delay) > 0 {
+		anim.Delay[len(anim.Delay)-1] += 100
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+func (r *Recorder) Save(filepath string) error {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	if err := r.Encode(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Close writes the animation to the file given with -record. It does nothing
+// for a nil recorder or one created with New.
+func (r *Recorder) Close() error {
+	if r == nil || r.path == "" {
+		return nil
+	}
+	return r.Save(r.path)
+}
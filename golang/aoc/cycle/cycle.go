@@ -0,0 +1,141 @@
+// Package cycle finds the cycle an iterated simulation runs into and uses it
+// to skip ahead to the state after an arbitrary number of steps.
+//
+// Step functions must not modify their argument, as the detectors keep
+// earlier states around to compare against.
+package cycle
+
+import "context"
+
+// Cycle describes the states s_i = step^i(initial) of a simulation: from
+// index Start on the states repeat with period Length.
+type Cycle struct {
+	Start  int
+	Length int
+}
+
+// Reduce returns the smallest index whose state equals the state at index n.
+func (c Cycle) Reduce(n int) int {
+	if n < c.Start {
+		return n
+	}
+	return c.Start + (n-c.Start)%c.Length
+}
+
+// Hash detects the cycle by remembering the key of every state seen so far.
+// It needs memory for every state before the cycle closes, but only steps
+// through the states once.
+func Hash[S any, K comparable](initial S, step func(S) S, key func(S) K) Cycle {
+	c, _, _ := hash(context.Background(), initial, step, key, -1)
+	return c
+}
+
+// hash returns the cycle together with all states up to the first repeated
+// one. It stops early once the history holds the state at index limit, in
+// which case the returned cycle is zero.
+func hash[S any, K comparable](ctx context.Context, initial S, step func(S) S, key func(S) K, limit int) (Cycle, []S, error) {
+	seen := map[K]int{key(initial): 0}
+	history := []S{initial}
+	state := initial
+	for i := 1; ; i++ {
+		if i-1 == limit {
+			return Cycle{}, history, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return Cycle{}, nil, err
+		}
+		state = step(state)
+		k := key(state)
+		if start, ok := seen[k]; ok {
+			return Cycle{Start: start, Length: i - start}, history, nil
+		}
+		seen[k] = i
+		history = append(history, state)
+	}
+}
+
+// Floyd detects the cycle with Floyd's tortoise and hare algorithm, which
+// keeps only two states in memory at the price of stepping several times
+// through the sequence.
+func Floyd[S any](initial S, step func(S) S, equal func(S, S) bool) Cycle {
+	tortoise := step(initial)
+	hare := step(step(initial))
+	for !equal(tortoise, hare) {
+		tortoise = step(tortoise)
+		hare = step(step(hare))
+	}
+	start := 0
+	tortoise = initial
+	for !equal(tortoise, hare) {
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+	length := 1
+	hare = step(tortoise)
+	for !equal(tortoise, hare) {
+		hare = step(hare)
+		length++
+	}
+	return Cycle{Start: start, Length: length}
+}
+
+// Brent detects the cycle with Brent's algorithm. Like Floyd it keeps only
+// two states in memory but usually needs fewer steps.
+func Brent[S any](initial S, step func(S) S, equal func(S, S) bool) Cycle {
+	power, length := 1, 1
+	tortoise := initial
+	hare := step(initial)
+	for !equal(tortoise, hare) {
+		if power == length {
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+		hare = step(hare)
+		length++
+	}
+	tortoise, hare = initial, initial
+	for i := 0; i < length; i++ {
+		hare = step(hare)
+	}
+	start := 0
+	for !equal(tortoise, hare) {
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+	return Cycle{Start: start, Length: length}
+}
+
+// Advance returns the state after n steps by stepping only as far as the
+// cycle requires.
+func Advance[S any](initial S, step func(S) S, c Cycle, n int) S {
+	state := initial
+	for i := c.Reduce(n); i > 0; i-- {
+		state = step(state)
+	}
+	return state
+}
+
+// Nth returns the state after n steps, detecting the cycle by hashing. The
+// states seen while looking for the cycle are reused, so no step is done
+// twice.
+func Nth[S any, K comparable](initial S, step func(S) S, key func(S) K, n int) S {
+	state, _ := NthContext(context.Background(), initial, step, key, n)
+	return state
+}
+
+// NthContext is like Nth but gives up with the context's error once it is
+// cancelled.
+func NthContext[S any, K comparable](ctx context.Context, initial S, step func(S) S, key func(S) K, n int) (S, error) {
+	c, history, err := hash(ctx, initial, step, key, n)
+	if err != nil {
+		var zero S
+		return zero, err
+	}
+	if c.Length == 0 {
+		return history[n], nil
+	}
+	return history[c.Reduce(n)], nil
+}
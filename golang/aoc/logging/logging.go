@@ -0,0 +1,88 @@
+// Package logging provides the debug logger shared by the days. Nothing is
+// logged unless it is enabled on the command line, so normal runs only print
+// the answers.
+//
+// Importing the package registers the flags
+//
+//	-v         log debug information
+//	-vv        log detailed traces as well
+//	-log-days  only log for these days, e.g. "18,21" (default $AOC_LOG_DAYS)
+//
+// which take effect once Setup is called after flag.Parse.
+package logging
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LevelTrace is below slog.LevelDebug and used for per-step output.
+const LevelTrace = slog.LevelDebug - 4
+
+var (
+	verbose     = flag.Bool("v", false, "log debug information")
+	veryVerbose = flag.Bool("vv", false, "log detailed traces as well")
+	logDays     = flag.String("log-days", os.Getenv("AOC_LOG_DAYS"), "comma separated days to log for, all if empty")
+)
+
+var logger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.Level(math.MaxInt)}))
+
+// dayNumber turns "day07", "07" and "7" into 7.
+func dayNumber(day string) int {
+	num, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(day), "day"))
+	if err != nil {
+		return -1
+	}
+	return num
+}
+
+func isLogged(day string) bool {
+	if *logDays == "" {
+		return true
+	}
+	for _, logDay := range strings.Split(*logDays, ",") {
+		if dayNumber(logDay) == dayNumber(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Level returns the level selected by the flags for the given day.
+func Level(day string) slog.Level {
+	if !isLogged(day) {
+		return slog.LevelInfo
+	}
+	if *veryVerbose {
+		return LevelTrace
+	}
+	if *verbose {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+// Setup enables logging to stderr for the given day, e.g. "day18", at the
+// level selected by the flags. It has to be called after flag.Parse.
+func Setup(day string) {
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: Level(day)})
+	logger = slog.New(handler).With("day", day)
+}
+
+func Logger() *slog.Logger {
+	return logger
+}
+
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+func Trace(msg string, args ...any) {
+	logger.Log(context.Background(), LevelTrace, msg, args...)
+}
@@ -0,0 +1,295 @@
+// Package render draws a grid together with overlays such as paths,
+// highlighted cells and heatmaps as plain text, ANSI coloured terminal output
+// or PNG images.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+type Point struct {
+	X, Y int
+}
+
+// Grid is the base layer of a picture.
+type Grid interface {
+	Size() (width, height int)
+	At(p Point) rune
+}
+
+// Runes is a grid backed by rows of runes. Rows may be ragged, missing cells
+// are drawn as spaces.
+type Runes [][]rune
+
+func (r Runes) Size() (int, int) {
+	width := 0
+	for _, row := range r {
+		width = max(width, len(row))
+	}
+	return width, len(r)
+}
+
+func (r Runes) At(p Point) rune {
+	if p.Y < 0 || p.Y >= len(r) || p.X < 0 || p.X >= len(r[p.Y]) {
+		return ' '
+	}
+	return r[p.Y][p.X]
+}
+
+func FromLines(lines []string) Runes {
+	runes := make(Runes, len(lines))
+	for i, line := range lines {
+		runes[i] = []rune(line)
+	}
+	return runes
+}
+
+// Blank is a grid of the given size filled with a single rune.
+type Blank struct {
+	Width, Height int
+	Fill          rune
+}
+
+func (b Blank) Size() (int, int) {
+	return b.Width, b.Height
+}
+
+func (b Blank) At(Point) rune {
+	return b.Fill
+}
+
+// Cell is what ends up on screen for one grid position. Nil colours leave the
+// terminal default or the palette colour in place.
+type Cell struct {
+	Rune       rune
+	Foreground color.Color
+	Background color.Color
+}
+
+// Overlay modifies the cells of a picture. Overlays are applied in the order
+// they were added, so later ones win.
+type Overlay interface {
+	Apply(cells [][]Cell)
+}
+
+func inside(cells [][]Cell, p Point) bool {
+	return p.Y >= 0 && p.Y < len(cells) && p.X >= 0 && p.X < len(cells[p.Y])
+}
+
+// Path marks the given points with a rune and a foreground colour. A zero
+// rune keeps the underlying one.
+type Path struct {
+	Points []Point
+	Rune   rune
+	Color  color.Color
+}
+
+func (o Path) Apply(cells [][]Cell) {
+	for _, p := range o.Points {
+		if !inside(cells, p) {
+			continue
+		}
+		if o.Rune != 0 {
+			cells[p.Y][p.X].Rune = o.Rune
+		}
+		if o.Color != nil {
+			cells[p.Y][p.X].Foreground = o.Color
+		}
+	}
+}
+
+// Highlight sets the background colour of the given points.
+type Highlight struct {
+	Points []Point
+	Color  color.Color
+}
+
+func (o Highlight) Apply(cells [][]Cell) {
+	for _, p := range o.Points {
+		if inside(cells, p) {
+			cells[p.Y][p.X].Background = o.Color
+		}
+	}
+}
+
+// Heatmap colours the background of every point with a value, interpolating
+// between From for the lowest and To for the highest value.
+type Heatmap struct {
+	Values   map[Point]int
+	From, To color.Color
+}
+
+func (o Heatmap) Apply(cells [][]Cell) {
+	if len(o.Values) == 0 {
+		return
+	}
+	from, to := o.From, o.To
+	if from == nil {
+		from = color.RGBA{0, 0, 255, 255}
+	}
+	if to == nil {
+		to = color.RGBA{255, 0, 0, 255}
+	}
+	low, high := 0, 0
+	first := true
+	for _, value := range o.Values {
+		if first || value < low {
+			low = value
+		}
+		if first || value > high {
+			high = value
+		}
+		first = false
+	}
+	for p, value := range o.Values {
+		if !inside(cells, p) {
+			continue
+		}
+		t := 0.0
+		if high > low {
+			t = float64(value-low) / float64(high-low)
+		}
+		cells[p.Y][p.X].Background = interpolate(from, to, t)
+	}
+}
+
+func interpolate(from, to color.Color, t float64) color.Color {
+	r1, g1, b1, _ := from.RGBA()
+	r2, g2, b2, _ := to.RGBA()
+	mix := func(a, b uint32) uint8 {
+		return uint8((float64(a)*(1-t) + float64(b)*t) / 257)
+	}
+	return color.RGBA{mix(r1, r2), mix(g1, g2), mix(b1, b2), 255}
+}
+
+// DefaultPalette gives the image colour of the runes most puzzles use for
+// walls and open space. Other runes are drawn in gray.
+var DefaultPalette = map[rune]color.Color{
+	'#': color.RGBA{200, 200, 200, 255},
+	'.': color.RGBA{20, 20, 20, 255},
+	' ': color.RGBA{0, 0, 0, 255},
+}
+
+var defaultColor = color.RGBA{110, 110, 110, 255}
+
+type Picture struct {
+	Grid     Grid
+	Overlays []Overlay
+	Palette  map[rune]color.Color
+}
+
+func New(grid Grid, overlays ...Overlay) *Picture {
+	return &Picture{Grid: grid, Overlays: overlays, Palette: DefaultPalette}
+}
+
+func (p *Picture) Add(overlays ...Overlay) *Picture {
+	p.Overlays = append(p.Overlays, overlays...)
+	return p
+}
+
+// Cells returns the grid with all overlays applied.
+func (p *Picture) Cells() [][]Cell {
+	width, height := p.Grid.Size()
+	cells := make([][]Cell, height)
+	for y := range cells {
+		cells[y] = make([]Cell, width)
+		for x := range cells[y] {
+			cells[y][x].Rune = p.Grid.At(Point{x, y})
+		}
+	}
+	for _, overlay := range p.Overlays {
+		overlay.Apply(cells)
+	}
+	return cells
+}
+
+// Text renders the picture without colours, one line per row.
+func (p *Picture) Text() string {
+	sb := strings.Builder{}
+	for _, row := range p.Cells() {
+		for _, cell := range row {
+			sb.WriteRune(cell.Rune)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func ansiColor(code int, c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", code, r>>8, g>>8, b>>8)
+}
+
+// ANSI renders the picture with 24-bit colour escape sequences.
+func (p *Picture) ANSI() string {
+	sb := strings.Builder{}
+	for _, row := range p.Cells() {
+		for _, cell := range row {
+			if cell.Foreground != nil {
+				sb.WriteString(ansiColor(38, cell.Foreground))
+			}
+			if cell.Background != nil {
+				sb.WriteString(ansiColor(48, cell.Background))
+			}
+			sb.WriteRune(cell.Rune)
+			if cell.Foreground != nil || cell.Background != nil {
+				sb.WriteString("\x1b[0m")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (p *Picture) paletteColor(r rune) color.Color {
+	if c, ok := p.Palette[r]; ok {
+		return c
+	}
+	return defaultColor
+}
+
+// Image draws every cell as a cellSize square in its background or palette
+// colour, with the foreground colour as a smaller square in the middle.
+func (p *Picture) Image(cellSize int) *image.RGBA {
+	cells := p.Cells()
+	width, height := p.Grid.Size()
+	img := image.NewRGBA(image.Rect(0, 0, width*cellSize, height*cellSize))
+	margin := cellSize / 4
+	for y, row := range cells {
+		for x, cell := range row {
+			rect := image.Rect(x*cellSize, y*cellSize, (x+1)*cellSize, (y+1)*cellSize)
+			background := cell.Background
+			if background == nil {
+				background = p.paletteColor(cell.Rune)
+			}
+			draw.Draw(img, rect, image.NewUniform(background), image.Point{}, draw.Src)
+			if cell.Foreground != nil {
+				draw.Draw(img, rect.Inset(margin), image.NewUniform(cell.Foreground), image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
+
+func (p *Picture) WritePNG(w io.Writer, cellSize int) error {
+	return png.Encode(w, p.Image(cellSize))
+}
+
+func (p *Picture) SavePNG(filepath string, cellSize int) error {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	if err := p.WritePNG(file, cellSize); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
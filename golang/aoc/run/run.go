@@ -0,0 +1,65 @@
+// Package run runs the parts of a day under the time budget given with the
+// -timeout flag, so a part that runs away on unexpected input is aborted
+// instead of hanging.
+package run
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+var budget = flag.Duration("timeout", 0, "time budget for each part, e.g. 30s, 0 for no limit")
+
+type TimeoutError struct {
+	Day, Part int
+	Budget    time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out in day %d part %d after %s", e.Day, e.Part, e.Budget)
+}
+
+// Part calls solve with a context that is cancelled once the budget is used
+// up. Solvers are expected to check the context in their main loops, but
+// Part returns a TimeoutError when the budget is exceeded even if solve
+// keeps running.
+func Part(day, part int, solve func(ctx context.Context) error) error {
+	_, err := Answer(day, part, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, solve(ctx)
+	})
+	return err
+}
+
+// Answer is like Part for solvers that return their answer.
+func Answer[T any](day, part int, solve func(ctx context.Context) (T, error)) (T, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if *budget > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), *budget)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	type result struct {
+		answer T
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		answer, err := solve(ctx)
+		done <- result{answer, err}
+	}()
+	var zero T
+	select {
+	case r := <-done:
+		if errors.Is(r.err, context.DeadlineExceeded) {
+			return zero, &TimeoutError{day, part, *budget}
+		}
+		return r.answer, r.err
+	case <-ctx.Done():
+		return zero, &TimeoutError{day, part, *budget}
+	}
+}
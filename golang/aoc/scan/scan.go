@@ -0,0 +1,163 @@
+// Package scan finds the tokens of a vocabulary in text in a single pass,
+// including overlapping ones, e.g. to read digits that are written as
+// words.
+package scan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Vocabulary maps the tokens that stand for a digit to its value.
+type Vocabulary map[string]int
+
+// Digits are the decimal digits themselves.
+var Digits = Vocabulary{
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4,
+	"5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+}
+
+// Merge returns a vocabulary with the tokens of both, other wins.
+func (v Vocabulary) Merge(other Vocabulary) Vocabulary {
+	merged := Vocabulary{}
+	for token, digit := range v {
+		merged[token] = digit
+	}
+	for token, digit := range other {
+		merged[token] = digit
+	}
+	return merged
+}
+
+// ParseVocabulary reads pairs like "eins=1,zwei=2".
+func ParseVocabulary(s string) (Vocabulary, error) {
+	vocabulary := Vocabulary{}
+	for _, pair := range strings.Split(s, ",") {
+		token, digit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("invalid vocabulary entry %q", pair)
+		}
+		value, err := strconv.Atoi(digit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vocabulary entry %q: %w", pair, err)
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("invalid vocabulary entry %q: negative value", pair)
+		}
+		vocabulary[token] = value
+	}
+	return vocabulary, nil
+}
+
+type Match struct {
+	Token string
+	Digit int
+	// Start is the byte offset of the token in the line.
+	Start int
+}
+
+// Scanner finds all tokens of a vocabulary in a line in a single pass,
+// including overlapping ones like "one" and "eight" in "oneight". It is an
+// Aho-Corasick automaton over the bytes of the tokens, with the failure
+// links resolved into a full transition table.
+type Scanner struct {
+	table  [][256]int
+	output [][]int
+	tokens []string
+	digits []int
+}
+
+func New(vocabulary Vocabulary) *Scanner {
+	s := &Scanner{output: [][]int{nil}}
+	trie := []map[byte]int{{}}
+	for token, digit := range vocabulary {
+		if token == "" {
+			continue
+		}
+		state := 0
+		for i := 0; i < len(token); i++ {
+			nextState, ok := trie[state][token[i]]
+			if !ok {
+				nextState = len(trie)
+				trie = append(trie, map[byte]int{})
+				s.output = append(s.output, nil)
+				trie[state][token[i]] = nextState
+			}
+			state = nextState
+		}
+		s.output[state] = append(s.output[state], len(s.tokens))
+		s.tokens = append(s.tokens, token)
+		s.digits = append(s.digits, digit)
+	}
+	// breadth first, so the transitions of the failure state are known
+	s.table = make([][256]int, len(trie))
+	fail := make([]int, len(trie))
+	queue := []int{0}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for c := 0; c < 256; c++ {
+			child, ok := trie[state][byte(c)]
+			if !ok {
+				if state != 0 {
+					s.table[state][c] = s.table[fail[state]][c]
+				}
+				continue
+			}
+			s.table[state][c] = child
+			if state != 0 {
+				fail[child] = s.table[fail[state]][c]
+			}
+			s.output[child] = append(s.output[child], s.output[fail[child]]...)
+			queue = append(queue, child)
+		}
+	}
+	return s
+}
+
+// Scan calls fn for every match in the line, ordered by where they end.
+func (s *Scanner) Scan(line string, fn func(Match)) {
+	state := 0
+	for i := 0; i < len(line); i++ {
+		state = s.table[state][line[i]]
+		for _, token := range s.output[state] {
+			fn(Match{Token: s.tokens[token], Digit: s.digits[token], Start: i + 1 - len(s.tokens[token])})
+		}
+	}
+}
+
+// FirstLast returns the matches starting first and last in the line. Of
+// matches starting at the same position the longest wins.
+func (s *Scanner) FirstLast(line string) (first Match, last Match, ok bool) {
+	s.Scan(line, func(m Match) {
+		if !ok || m.Start < first.Start || m.Start == first.Start && len(m.Token) > len(first.Token) {
+			first = m
+		}
+		if !ok || m.Start > last.Start || m.Start == last.Start && len(m.Token) > len(last.Token) {
+			last = m
+		}
+		ok = true
+	})
+	return first, last, ok
+}
+
+// Calibration returns the value made of the first and the last token of the
+// line, ok is false if there is none.
+func (s *Scanner) Calibration(line string) (int, bool) {
+	first, last, ok := s.FirstLast(line)
+	if !ok {
+		return 0, false
+	}
+	return Join(first.Digit, last.Digit), true
+}
+
+// Join returns the number written as first followed by last, so values of
+// more than one digit like 10 for "ten" keep all their digits.
+func Join(first, last int) int {
+	shift := 10
+	for rest := last; rest >= 10; rest /= 10 {
+		shift *= 10
+	}
+	return first*shift + last
+}
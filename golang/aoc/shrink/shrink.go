@@ -0,0 +1,244 @@
+// Package shrink minimizes an input that makes a day fail while keeping the
+// failure, and rewrites the identifiers in it, so the reduced case can be
+// shared and committed without giving away the original input.
+//
+// Importing the package registers the flags
+//
+//	-shrink          shrink input.txt and write the result to this file
+//	-shrink-if       the failure to keep, see below (default "crash")
+//	-shrink-timeout  time limit for checking one candidate
+//
+// A candidate is checked by running the day's binary again in a temporary
+// directory with the candidate as input.txt, so panics, log.Fatal and endless
+// loops on broken candidates don't stop the shrinking. The failure is one of
+//
+//	crash          the day exits with an error
+//	timeout        the day doesn't finish within -shrink-timeout
+//	exec:<cmd>     the shell command exits with status 0, with the candidate
+//	               file as $1
+//
+// or one of the predicates the day defines.
+package shrink
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	output    = flag.String("shrink", "", "shrink input.txt while it keeps failing and write the anonymized result to this file")
+	condition = flag.String("shrink-if", "crash", "failure to keep while shrinking: crash, timeout, exec:<command> or a predicate of the day")
+	timeout   = flag.Duration("shrink-timeout", 10*time.Second, "time limit for checking one candidate")
+	check     = flag.String("shrink-check", "", "exit with status 0 if input.txt shows the failure of this predicate of the day")
+)
+
+// Day describes how to shrink the input of one day. All fields are optional.
+type Day struct {
+	// Predicates report whether input.txt in the working directory shows a
+	// failure. They run in a separate process and may panic.
+	Predicates map[string]func() bool
+	// Reduce minimizes the input. It defaults to removing lines.
+	Reduce func(input string, fails func(string) bool) string
+	// Anonymize rewrites the identifiers of the reduced input.
+	Anonymize func(input string) string
+}
+
+// Main handles the shrink flags and reports whether it did, in which case
+// the day should not run its parts. It has to be called after flag.Parse.
+func Main(day Day) bool {
+	if *check != "" {
+		predicate, ok := day.Predicates[*check]
+		if !ok {
+			log.Fatalf("unknown predicate %q", *check)
+		}
+		if !predicate() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if *output == "" {
+		return false
+	}
+	if err := shrink(day); err != nil {
+		log.Fatal(err)
+	}
+	return true
+}
+
+func shrink(day Day) error {
+	content, err := os.ReadFile("input.txt")
+	if err != nil {
+		return err
+	}
+	fails, err := failure(day)
+	if err != nil {
+		return err
+	}
+	input := string(content)
+	if !fails(input) {
+		return fmt.Errorf("input.txt does not fail with %s", *condition)
+	}
+	reduce := day.Reduce
+	if reduce == nil {
+		reduce = Lines
+	}
+	reduced := reduce(input, fails)
+	log.Printf("Shrunk input from %d to %d lines\n", strings.Count(input, "\n")+1, strings.Count(reduced, "\n")+1)
+	if day.Anonymize != nil {
+		anonymized := day.Anonymize(reduced)
+		if !fails(anonymized) {
+			return errors.New("the anonymized input does not fail anymore")
+		}
+		reduced = anonymized
+	}
+	return os.WriteFile(*output, []byte(reduced), 0644)
+}
+
+// failure returns a function that checks a candidate input in a separate
+// process.
+func failure(day Day) (func(string) bool, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	command := func(ctx context.Context, dir string) *exec.Cmd {
+		return exec.CommandContext(ctx, executable, "-timeout=0")
+	}
+	expected := func(ctx context.Context, err error) bool { return err != nil && ctx.Err() == nil }
+	switch {
+	case *condition == "crash":
+	case *condition == "timeout":
+		expected = func(ctx context.Context, err error) bool { return ctx.Err() != nil }
+	case strings.HasPrefix(*condition, "exec:"):
+		shell := strings.TrimPrefix(*condition, "exec:")
+		command = func(ctx context.Context, dir string) *exec.Cmd {
+			return exec.CommandContext(ctx, "sh", "-c", shell, "sh", filepath.Join(dir, "input.txt"))
+		}
+		expected = func(ctx context.Context, err error) bool { return err == nil && ctx.Err() == nil }
+	default:
+		if _, ok := day.Predicates[*condition]; !ok {
+			return nil, fmt.Errorf("unknown failure %q", *condition)
+		}
+		predicate := *condition
+		command = func(ctx context.Context, dir string) *exec.Cmd {
+			return exec.CommandContext(ctx, executable, "-shrink-check="+predicate)
+		}
+		expected = func(ctx context.Context, err error) bool { return err == nil && ctx.Err() == nil }
+	}
+	return func(input string) bool {
+		dir, err := os.MkdirTemp("", "shrink")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte(input), 0644); err != nil {
+			log.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		cmd := command(ctx, dir)
+		cmd.Dir = dir
+		return expected(ctx, cmd.Run())
+	}, nil
+}
+
+// Slice removes chunks of items as long as the rest is still interesting,
+// starting with halves and going down to single items.
+func Slice[T any](items []T, interesting func([]T) bool) []T {
+	chunks := 2
+	for len(items) >= 2 {
+		size := (len(items) + chunks - 1) / chunks
+		reduced := false
+		for start := 0; start < len(items); start += size {
+			rest := append(slices.Clone(items[:start]), items[min(start+size, len(items)):]...)
+			if interesting(rest) {
+				items = rest
+				chunks = max(chunks-1, 2)
+				reduced = true
+				break
+			}
+		}
+		if !reduced {
+			if size == 1 {
+				break
+			}
+			chunks = min(chunks*2, len(items))
+		}
+	}
+	return items
+}
+
+// Lines removes lines of the input as long as it keeps failing. A trailing
+// newline is kept as it is.
+func Lines(input string, fails func(string) bool) string {
+	trailing := ""
+	if strings.HasSuffix(input, "\n") {
+		input, trailing = strings.TrimSuffix(input, "\n"), "\n"
+	}
+	lines := Slice(strings.Split(input, "\n"), func(lines []string) bool {
+		return fails(strings.Join(lines, "\n") + trailing)
+	})
+	return strings.Join(lines, "\n") + trailing
+}
+
+// Renamer consistently maps identifiers to generated names.
+type Renamer struct {
+	name  func(id string, n int) string
+	names map[string]string
+	used  map[string]bool
+	next  int
+}
+
+// NewRenamer returns a renamer that names the n-th new identifier
+// name(id, n), skipping names already taken. The kept identifiers are not
+// renamed.
+func NewRenamer(name func(id string, n int) string, keep ...string) *Renamer {
+	r := &Renamer{name: name, names: map[string]string{}, used: map[string]bool{}}
+	for _, id := range keep {
+		r.names[id] = id
+		r.used[id] = true
+	}
+	return r
+}
+
+func (r *Renamer) Rename(id string) string {
+	if name, ok := r.names[id]; ok {
+		return name
+	}
+	name := r.name(id, r.next)
+	for r.used[name] {
+		r.next++
+		name = r.name(id, r.next)
+	}
+	r.next++
+	r.names[id] = name
+	r.used[name] = true
+	return name
+}
+
+// ReplaceAll renames every match of the pattern in s.
+func (r *Renamer) ReplaceAll(pattern *regexp.Regexp, s string) string {
+	return pattern.ReplaceAllStringFunc(s, r.Rename)
+}
+
+// Letters returns the n-th name of at least width characters from the
+// alphabet, e.g. "aa", "ab", ... for the lowercase letters.
+func Letters(n, width int, alphabet string) string {
+	name := []byte{}
+	for i := 0; i < width || n > 0; i++ {
+		name = append(name, alphabet[n%len(alphabet)])
+		n /= len(alphabet)
+	}
+	slices.Reverse(name)
+	return string(name)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"aoc/scan"
+)
+
+var (
+	diagnose  = flag.Int("diagnose", 0, "print the matches of every line for this part instead of solving")
+	csvOutput = flag.Bool("csv", false, "print the diagnostics as CSV")
+)
+
+type Diagnostic struct {
+	Line        int
+	Text        string
+	First, Last scan.Match
+	Value       int
+	// NoDigit lines have no match at all and no value.
+	NoDigit bool
+	// Ambiguous lines have a first or last match that overlaps another
+	// match, like "one" and "eight" in "oneight", so reading the line left
+	// to right without overlaps would give a different value.
+	Ambiguous bool
+}
+
+func (d Diagnostic) Flags() string {
+	flags := []string{}
+	if d.NoDigit {
+		flags = append(flags, "no-digit")
+	}
+	if d.Ambiguous {
+		flags = append(flags, "ambiguous")
+	}
+	return strings.Join(flags, " ")
+}
+
+func overlaps(a, b scan.Match) bool {
+	return a.Start < b.Start+len(b.Token) && b.Start < a.Start+len(a.Token)
+}
+
+func diagnoseLine(scanner *scan.Scanner, number int, line string) Diagnostic {
+	d := Diagnostic{Line: number, Text: line}
+	matches := []scan.Match{}
+	scanner.Scan(line, func(m scan.Match) {
+		matches = append(matches, m)
+	})
+	first, last, ok := scanner.FirstLast(line)
+	if !ok {
+		d.NoDigit = true
+		return d
+	}
+	d.First, d.Last = first, last
+	d.Value = scan.Join(first.Digit, last.Digit)
+	for _, m := range matches {
+		if m != first && overlaps(m, first) || m != last && overlaps(m, last) {
+			d.Ambiguous = true
+		}
+	}
+	return d
+}
+
+func runDiagnostics(w io.Writer, part int) error {
+	scanner, err := scannerForPart(part)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var csvWriter *csv.Writer
+	if *csvOutput {
+		csvWriter = csv.NewWriter(w)
+		csvWriter.Write([]string{"line", "text", "first", "first_start", "last", "last_start", "value", "flags"})
+	}
+	lines := bufio.NewScanner(file)
+	for number := 1; lines.Scan(); number++ {
+		d := diagnoseLine(scanner, number, lines.Text())
+		if csvWriter != nil {
+			record := []string{strconv.Itoa(d.Line), d.Text, "", "", "", "", "", d.Flags()}
+			if !d.NoDigit {
+				record[2], record[3] = d.First.Token, strconv.Itoa(d.First.Start)
+				record[4], record[5] = d.Last.Token, strconv.Itoa(d.Last.Start)
+				record[6] = strconv.Itoa(d.Value)
+			}
+			csvWriter.Write(record)
+			continue
+		}
+		if d.NoDigit {
+			fmt.Fprintf(w, "%d: %q no-digit\n", d.Line, d.Text)
+			continue
+		}
+		fmt.Fprintf(w, "%d: %q first %q at %d, last %q at %d, value %d",
+			d.Line, d.Text, d.First.Token, d.First.Start, d.Last.Token, d.Last.Start, d.Value)
+		if d.Ambiguous {
+			fmt.Fprint(w, " ambiguous")
+		}
+		fmt.Fprintln(w)
+	}
+	if err := lines.Err(); err != nil {
+		return err
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	return nil
+}
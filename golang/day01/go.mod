@@ -1,3 +1,7 @@
 module day01
 
 go 1.21.5
+
+require aoc v0.0.0
+
+replace aoc => ../aoc
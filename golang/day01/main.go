@@ -1,116 +1,93 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
+	"log"
 	"os"
-	"regexp"
-	"strconv"
-	"unicode"
+
+	"aoc/run"
+	"aoc/scan"
 )
 
-var NUMBERS = map[string]string{
-	"one":   "1",
-	"two":   "2",
-	"three": "3",
-	"four":  "4",
-	"five":  "5",
-	"six":   "6",
-	"seven": "7",
-	"eight": "8",
-	"nine":  "9",
+var NUMBERS = scan.Vocabulary{
+	"one":   1,
+	"two":   2,
+	"three": 3,
+	"four":  4,
+	"five":  5,
+	"six":   6,
+	"seven": 7,
+	"eight": 8,
+	"nine":  9,
 }
 
-func solutionPart1() error {
-	file, err := os.Open("input.txt")
-	if err != nil {
-		return err
+var words = flag.String("words", "", "spelled digits for part 2 as word=digit pairs, e.g. eins=1,zwei=2 (default English)")
+
+func scannerForPart(part int) (*scan.Scanner, error) {
+	if part == 1 {
+		return scan.New(scan.Digits), nil
 	}
-	scanner := bufio.NewScanner(file)
-	lineNumbers := make([]int, 0)
-	for scanner.Scan() {
-		line := []rune(scanner.Text())
-		lineNumber := make([]rune, 2)
-		for _, c := range line {
-			if unicode.IsDigit(c) {
-				lineNumber[0] = c
-				break
-			}
-		}
-		for i := len(line) - 1; i >= 0; i-- {
-			if unicode.IsDigit(line[i]) {
-				lineNumber[1] = line[i]
-				break
-			}
-		}
-		parsedNumber, err := strconv.Atoi(string(lineNumber))
+	vocabulary := NUMBERS
+	if *words != "" {
+		var err error
+		vocabulary, err = scan.ParseVocabulary(*words)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		lineNumbers = append(lineNumbers, parsedNumber)
 	}
-	sum := 0
-	for _, lineNumber := range lineNumbers {
-		sum += lineNumber
+	return scan.New(scan.Digits.Merge(vocabulary)), nil
+}
+
+func solutionPart1(ctx context.Context) error {
+	scanner, err := scannerForPart(1)
+	if err != nil {
+		return err
+	}
+	sum, err := calibrateFile(ctx, scanner)
+	if err != nil {
+		return err
 	}
 	println(sum)
 	return nil
 }
 
-func solutionPart2() error {
-	file, err := os.Open("input.txt")
+func solutionPart2(ctx context.Context) error {
+	scanner, err := scannerForPart(2)
 	if err != nil {
 		return err
 	}
-	lineNumbers := make([]int, 0)
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lineNumber := ""
-		reDigits, err := regexp.Compile(`(one|two|three|four|five|six|seven|eight|nine|\d)`)
-		if err != nil {
-			return err
-		}
-		reReversedDigits, err := regexp.Compile(`(eno|owt|eerht|ruof|evif|xis|neves|thgie|enin|\d)`)
-		if err != nil {
-			return err
-		}
-		line := scanner.Text()
-		firstDigit := reDigits.FindString(line)
-		if len(firstDigit) > 1 {
-			lineNumber += NUMBERS[firstDigit]
-		} else {
-			lineNumber += firstDigit
-		}
-		reversedLine := []rune(line)
-		for i, j := 0, len(reversedLine)-1; i < j; i, j = i+1, j-1 {
-			reversedLine[i], reversedLine[j] = reversedLine[j], reversedLine[i]
-		}
-		lastDigitReversed := reReversedDigits.FindString(string(reversedLine))
-		lastDigit := []rune(lastDigitReversed)
-		for i, j := 0, len(lastDigit)-1; i < j; i, j = i+1, j-1 {
-			lastDigit[i], lastDigit[j] = lastDigit[j], lastDigit[i]
-		}
-		if len(lastDigit) > 1 {
-			lineNumber += NUMBERS[string(lastDigit)]
-		} else {
-			lineNumber += lastDigitReversed
-		}
-		parsedNumber, err := strconv.Atoi(lineNumber)
-		if err != nil {
-			return err
-		}
-		lineNumbers = append(lineNumbers, parsedNumber)
-	}
-	sum := 0
-	for _, lineNumber := range lineNumbers {
-		sum += lineNumber
+	sum, err := calibrateFile(ctx, scanner)
+	if err != nil {
+		return err
 	}
 	println(sum)
 	return nil
 }
 
 func main() {
-	err := solutionPart2()
-	if err != nil {
-		panic(err)
+	flag.Parse()
+	if *generate > 0 {
+		if err := generateInput(os.Stdout, *generate); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *diagnose != 0 {
+		if err := runDiagnostics(os.Stdout, *diagnose); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// a line without a digit only breaks part 1, so part 2 runs anyway
+	failed := false
+	for part, solution := range []func(context.Context) error{solutionPart1, solutionPart2} {
+		if err := run.Part(1, part+1, solution); err != nil {
+			log.Println(err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"runtime"
+	"sync"
+
+	"aoc/scan"
+)
+
+var (
+	input     = flag.String("input", "input.txt", "calibration document to read")
+	workers   = flag.Int("workers", runtime.NumCPU(), "number of goroutines scanning chunks")
+	chunkSize = flag.Int("chunk-size", 1<<20, "approximate size of the chunks in bytes, chunks always end at a line break")
+	generate  = flag.Int("generate", 0, "write a random calibration document with this many lines to stdout instead of solving")
+)
+
+type chunk struct {
+	index     int
+	firstLine int
+	data      []byte
+}
+
+type chunkResult struct {
+	index int
+	sum   int
+	err   error
+}
+
+// readChunks splits the input into chunks of whole lines. Sending blocks
+// while all workers are busy, so only a few chunks are in memory at a time.
+func readChunks(ctx context.Context, r io.Reader, chunks chan<- chunk) error {
+	reader := bufio.NewReader(r)
+	line := 1
+	for index := 0; ; index++ {
+		data := make([]byte, max(*chunkSize, 1))
+		n, err := io.ReadFull(reader, data)
+		data = data[:n]
+		if err == nil {
+			rest, restErr := reader.ReadBytes('\n')
+			if restErr != nil && restErr != io.EOF {
+				return restErr
+			}
+			data = append(data, rest...)
+		} else if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		select {
+		case chunks <- chunk{index, line, data}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		line += bytes.Count(data, []byte{'\n'})
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func sumChunk(scanner *scan.Scanner, c chunk) (int, error) {
+	sum := 0
+	data := c.data
+	for number := c.firstLine; len(data) > 0; number++ {
+		var line []byte
+		line, data, _ = bytes.Cut(data, []byte{'\n'})
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		value, ok := scanner.Calibration(string(line))
+		if !ok {
+			return 0, fmt.Errorf("no digit in line %d %q, see -diagnose", number, line)
+		}
+		sum += value
+	}
+	return sum, nil
+}
+
+// calibrationSum scans the chunks of r in parallel. The sum doesn't depend on
+// the order the chunks finish in, and if several lines have no digit the
+// error is about the first of them.
+func calibrationSum(ctx context.Context, r io.Reader, scanner *scan.Scanner) (int, error) {
+	chunkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workerCount := max(*workers, 1)
+	chunks := make(chan chunk, workerCount)
+	results := make(chan chunkResult, workerCount)
+	var readErr error
+	go func() {
+		defer close(chunks)
+		readErr = readChunks(chunkCtx, r, chunks)
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				sum, err := sumChunk(scanner, c)
+				results <- chunkResult{c.index, sum, err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	sum := 0
+	var firstErr error
+	firstErrIndex := -1
+	for result := range results {
+		if result.err != nil {
+			if firstErrIndex == -1 || result.index < firstErrIndex {
+				firstErr, firstErrIndex = result.err, result.index
+			}
+			cancel()
+			continue
+		}
+		sum += result.sum
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	if readErr != nil {
+		return 0, readErr
+	}
+	return sum, nil
+}
+
+func calibrateFile(ctx context.Context, scanner *scan.Scanner) (int, error) {
+	file, err := os.Open(*input)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return calibrationSum(ctx, file, scanner)
+}
+
+// generateInput writes lines of random letters, digits and spelled digits,
+// each with at least one digit. The same number of lines always gives the
+// same document.
+func generateInput(w io.Writer, lines int) error {
+	random := rand.New(rand.NewSource(2023))
+	spelled := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+	out := bufio.NewWriter(w)
+	for i := 0; i < lines; i++ {
+		line := []byte{}
+		for len(line) < 10+random.Intn(40) {
+			switch random.Intn(8) {
+			case 0:
+				line = append(line, byte('1'+random.Intn(9)))
+			case 1:
+				line = append(line, spelled[random.Intn(len(spelled))]...)
+			default:
+				line = append(line, byte('a'+random.Intn(26)))
+			}
+		}
+		line = append(line, byte('1'+random.Intn(9)))
+		line = append(line, '\n')
+		if _, err := out.Write(line); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var (
+	likelihoodTotal = flag.Int("likelihood", 0, "print the most likely red/green/blue bags with this many cubes instead of solving")
+	likelihoodTop   = flag.Int("top", 5, "number of bags printed by -likelihood")
+)
+
+var likelihoodColors = []string{"red", "green", "blue"}
+
+// logChoose is the logarithm of the binomial coefficient, minus infinity if
+// k is out of range.
+func logChoose(n, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// drawLogLikelihood is the log probability of a draw when the cubes are
+// taken from the bag without replacement, a multivariate hypergeometric
+// distribution.
+func drawLogLikelihood(bag Bag, total int, draw map[string]int) float64 {
+	shown := 0
+	logLikelihood := 0.0
+	for _, color := range likelihoodColors {
+		logLikelihood += logChoose(bag[color], draw[color])
+		shown += draw[color]
+	}
+	if math.IsInf(logLikelihood, -1) || shown > total {
+		return math.Inf(-1)
+	}
+	return logLikelihood - logChoose(total, shown)
+}
+
+// GameLogLikelihood is the log probability of all draws of the game. The
+// cubes are put back after every draw, so the draws are independent.
+func GameLogLikelihood(bag Bag, total int, game Game) float64 {
+	logLikelihood := 0.0
+	for _, draw := range game.Draws {
+		logLikelihood += drawLogLikelihood(bag, total, draw)
+	}
+	return logLikelihood
+}
+
+type BagLikelihood struct {
+	Bag           Bag
+	LogLikelihood float64
+}
+
+// MostLikelyBags scores every split of total cubes into red, green and blue
+// by the likelihood of all games, most likely first.
+func MostLikelyBags(games []Game, total int) ([]BagLikelihood, error) {
+	for _, game := range games {
+		for _, draw := range game.Draws {
+			for color := range draw {
+				if !slices.Contains(likelihoodColors, color) {
+					return nil, fmt.Errorf("game %d shows %s cubes, only red, green and blue are supported", game.ID, color)
+				}
+			}
+		}
+	}
+	bags := make([]BagLikelihood, 0)
+	for red := 0; red <= total; red++ {
+		for green := 0; red+green <= total; green++ {
+			bag := Bag{"red": red, "green": green, "blue": total - red - green}
+			logLikelihood := 0.0
+			for _, game := range games {
+				logLikelihood += GameLogLikelihood(bag, total, game)
+			}
+			if !math.IsInf(logLikelihood, -1) {
+				bags = append(bags, BagLikelihood{bag, logLikelihood})
+			}
+		}
+	}
+	if len(bags) == 0 {
+		return nil, fmt.Errorf("no bag with %d cubes can produce the games", total)
+	}
+	slices.SortStableFunc(bags, func(a, b BagLikelihood) int {
+		if a.LogLikelihood > b.LogLikelihood {
+			return -1
+		}
+		if a.LogLikelihood < b.LogLikelihood {
+			return 1
+		}
+		return 0
+	})
+	return bags, nil
+}
+
+func printLikelihoods(total, top int) error {
+	games, err := load_game("input.txt")
+	if err != nil {
+		return err
+	}
+	bags, err := MostLikelyBags(games, total)
+	if err != nil {
+		return err
+	}
+	for _, bag := range bags[:min(max(top, 0), len(bags))] {
+		fmt.Printf("%s log-likelihood %.3f\n", bag.Bag, bag.LogLikelihood)
+	}
+	best := bags[0]
+	fmt.Println("Most likely bag:", best.Bag)
+	for _, game := range games {
+		fmt.Printf("game %d log-likelihood %.3f\n", game.ID, GameLogLikelihood(best.Bag, total, game))
+	}
+	return nil
+}
@@ -2,18 +2,178 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+
+	"aoc/run"
+)
+
+var (
+	bagFlag = flag.String("bag", "red=12,green=13,blue=14", "contents of the bag for part 1 as colour=count pairs")
+	subset  = flag.String("subset", "", "print the smallest bag that makes these comma separated games possible instead of solving")
+	explain = flag.Bool("explain", false, "print the draw that makes a game impossible in part 1")
 )
 
-var MAX_CUBES = map[string]int{
-	"red":   12,
-	"green": 13,
-	"blue":  14,
+type Game struct {
+	ID    int
+	Draws []map[string]int
+}
+
+// Bag holds the number of cubes of each colour.
+type Bag map[string]int
+
+// Power multiplies the counts of the given colours.
+func (b Bag) Power(colors ...string) int {
+	power := 1
+	for _, color := range colors {
+		power *= b[color]
+	}
+	return power
+}
+
+func parseBag(s string) (Bag, error) {
+	bag := Bag{}
+	for _, pair := range strings.Split(s, ",") {
+		color, count, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid bag entry %q", pair)
+		}
+		parsedCount, err := strconv.Atoi(count)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bag entry %q: %w", pair, err)
+		}
+		bag[color] = parsedCount
+	}
+	return bag, nil
+}
+
+func (b Bag) String() string {
+	colors := make([]string, 0, len(b))
+	for color := range b {
+		colors = append(colors, color)
+	}
+	slices.Sort(colors)
+	pairs := make([]string, len(colors))
+	for i, color := range colors {
+		pairs[i] = fmt.Sprintf("%s=%d", color, b[color])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Violation is a draw that shows more cubes of a colour than the bag holds.
+type Violation struct {
+	Game, Draw int
+	Color      string
+	Shown      int
+	Available  int
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("game %d draw %d shows %d %s, the bag holds %d", v.Game, v.Draw+1, v.Shown, v.Color, v.Available)
+}
+
+// Check returns the first draw of the game that is impossible with the bag,
+// or nil if the game is possible.
+func (b Bag) Check(g Game) *Violation {
+	for i, draw := range g.Draws {
+		colors := make([]string, 0, len(draw))
+		for color := range draw {
+			colors = append(colors, color)
+		}
+		slices.Sort(colors)
+		for _, color := range colors {
+			if draw[color] > b[color] {
+				return &Violation{Game: g.ID, Draw: i, Color: color, Shown: draw[color], Available: b[color]}
+			}
+		}
+	}
+	return nil
+}
+
+func (b Bag) Possible(games []Game) []Game {
+	possible := make([]Game, 0)
+	for _, game := range games {
+		if b.Check(game) == nil {
+			possible = append(possible, game)
+		}
+	}
+	return possible
+}
+
+// SmallestBag returns the smallest bag all the games are possible with.
+func SmallestBag(games []Game) Bag {
+	bag := Bag{}
+	for _, game := range games {
+		for color, count := range game.MinimalBag() {
+			bag[color] = max(bag[color], count)
+		}
+	}
+	return bag
+}
+
+// MaxPerColor returns the most cubes of each colour shown in a single draw.
+func (g Game) MaxPerColor() map[string]int {
+	maxCubes := make(map[string]int)
+	for _, draw := range g.Draws {
+		for color, count := range draw {
+			maxCubes[color] = max(maxCubes[color], count)
+		}
+	}
+	return maxCubes
+}
+
+// MinimalBag is the smallest bag the game is possible with.
+func (g Game) MinimalBag() Bag {
+	return Bag(g.MaxPerColor())
+}
+
+// TotalShown counts the cubes of all draws.
+func (g Game) TotalShown() int {
+	total := 0
+	for _, draw := range g.Draws {
+		for _, count := range draw {
+			total += count
+		}
+	}
+	return total
 }
 
-func load_game(filename string) (map[int]map[string]int, error) {
+func parseGame(line string) (Game, error) {
+	lineSplit := strings.Split(line, ":")
+	if len(lineSplit) != 2 {
+		return Game{}, fmt.Errorf("invalid game %q", line)
+	}
+	gameSplit := strings.Split(lineSplit[0], " ")
+	gameID, err := strconv.Atoi(gameSplit[len(gameSplit)-1])
+	if err != nil {
+		return Game{}, err
+	}
+	game := Game{ID: gameID}
+	for _, draw := range strings.Split(lineSplit[1], ";") {
+		cubes := make(map[string]int)
+		for _, cube := range strings.Split(draw, ",") {
+			cubeSplit := strings.Fields(cube)
+			if len(cubeSplit) != 2 {
+				return Game{}, fmt.Errorf("invalid cubes %q in game %d", cube, gameID)
+			}
+			cubeCount, err := strconv.Atoi(cubeSplit[0])
+			if err != nil {
+				return Game{}, err
+			}
+			cubes[cubeSplit[1]] += cubeCount
+		}
+		game.Draws = append(game.Draws, cubes)
+	}
+	return game, nil
+}
+
+func load_game(filename string) ([]Game, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -25,69 +185,101 @@ func load_game(filename string) (map[int]map[string]int, error) {
 			panic(err)
 		}
 	}(file)
-	gameData := make(map[int]map[string]int)
+	games := make([]Game, 0)
 	for scanner.Scan() {
-		line := scanner.Text()
-		lineSplit := strings.Split(line, ":")
-		gameSplit := strings.Split(lineSplit[0], " ")
-		gameID, err := strconv.Atoi(gameSplit[1])
+		game, err := parseGame(scanner.Text())
 		if err != nil {
 			return nil, err
 		}
-		maxCubes := map[string]int{
-			"red":   0,
-			"green": 0,
-			"blue":  0,
-		}
-		for _, draw := range strings.Split(lineSplit[1], ";") {
-			for _, cube := range strings.Split(draw, ",") {
-				cubeSplit := strings.Split(strings.TrimSpace(cube), " ")
-				cubeColor := cubeSplit[1]
-				cubeCount, err := strconv.Atoi(cubeSplit[0])
-				if err != nil {
-					return nil, err
-				}
-				maxCubes[cubeColor] = max(maxCubes[cubeColor], cubeCount)
-			}
-		}
-		gameData[gameID] = maxCubes
-
+		games = append(games, game)
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	return gameData, nil
+	return games, nil
 }
 
-func solutionPart1() {
-	gameData, err := load_game("input.txt")
+func solutionPart1(ctx context.Context) error {
+	games, err := load_game("input.txt")
 	if err != nil {
 		panic(err)
 	}
+	bag, err := parseBag(*bagFlag)
+	if err != nil {
+		return err
+	}
 	possibleGamesSum := 0
-	for gameIndex, game := range gameData {
-		if game["red"] <= MAX_CUBES["red"] &&
-			game["green"] <= MAX_CUBES["green"] &&
-			game["blue"] <= MAX_CUBES["blue"] {
-			possibleGamesSum += gameIndex
+	for _, game := range games {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		if violation := bag.Check(game); violation != nil {
+			if *explain {
+				fmt.Println(violation)
+			}
+			continue
+		}
+		possibleGamesSum += game.ID
 	}
 	println(possibleGamesSum)
+	return nil
 }
 
-func solutionPart2() {
-	gameData, err := load_game("input.txt")
+func printSmallestBag(ids string) error {
+	games, err := load_game("input.txt")
+	if err != nil {
+		return err
+	}
+	chosen := make([]Game, 0)
+	for _, id := range strings.Split(ids, ",") {
+		gameID, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil {
+			return err
+		}
+		index := slices.IndexFunc(games, func(g Game) bool { return g.ID == gameID })
+		if index == -1 {
+			return fmt.Errorf("no game %d", gameID)
+		}
+		chosen = append(chosen, games[index])
+	}
+	fmt.Println(SmallestBag(chosen))
+	return nil
+}
+
+func solutionPart2(ctx context.Context) error {
+	games, err := load_game("input.txt")
 	if err != nil {
 		panic(err)
 	}
 	gamePowerSum := 0
-	for _, game := range gameData {
-		gamePowerSum += game["red"] * game["blue"] * game["green"]
+	for _, game := range games {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		gamePowerSum += game.MinimalBag().Power("red", "green", "blue")
 	}
 	println(gamePowerSum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if *likelihoodTotal > 0 {
+		if err := printLikelihoods(*likelihoodTotal, *likelihoodTop); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *subset != "" {
+		if err := printSmallestBag(*subset); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := run.Part(2, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(2, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
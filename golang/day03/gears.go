@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	gearSymbols   = flag.String("gear-symbols", "*", "symbols that can be gears in part 2")
+	gearCount     = flag.String("gear-count", "=2", "numbers a gear needs, exactly (=2), at least (>=2) or one up to at most (<=3)")
+	gearAggregate = flag.String("gear-aggregate", "product", "how the numbers of a gear are combined: product, sum or max")
+)
+
+var aggregates = map[string]func([]int) int{
+	"product": func(values []int) int {
+		result := 1
+		for _, value := range values {
+			result *= value
+		}
+		return result
+	},
+	"sum": func(values []int) int {
+		result := 0
+		for _, value := range values {
+			result += value
+		}
+		return result
+	},
+	"max": func(values []int) int {
+		result := math.MinInt
+		for _, value := range values {
+			result = max(result, value)
+		}
+		return result
+	},
+}
+
+// GearRule decides which symbols are gears and what they are worth.
+type GearRule struct {
+	Symbols []rune
+	// MinNumbers is at least 1, a symbol without numbers is never a gear.
+	MinNumbers, MaxNumbers int
+	Aggregate              func([]int) int
+}
+
+func parseGearRule(symbols, count, aggregate string) (GearRule, error) {
+	rule := GearRule{Symbols: []rune(symbols), MinNumbers: 1, MaxNumbers: math.MaxInt}
+	var err error
+	switch {
+	case strings.HasPrefix(count, ">="):
+		rule.MinNumbers, err = strconv.Atoi(count[2:])
+	case strings.HasPrefix(count, "<="):
+		rule.MaxNumbers, err = strconv.Atoi(count[2:])
+	default:
+		rule.MinNumbers, err = strconv.Atoi(strings.TrimPrefix(count, "="))
+		rule.MaxNumbers = rule.MinNumbers
+	}
+	if err != nil {
+		return GearRule{}, fmt.Errorf("invalid gear count %q: %w", count, err)
+	}
+	if rule.MinNumbers < 1 || rule.MaxNumbers < rule.MinNumbers {
+		return GearRule{}, fmt.Errorf("invalid gear count %q: a gear needs at least one number", count)
+	}
+	var ok bool
+	rule.Aggregate, ok = aggregates[aggregate]
+	if !ok {
+		return GearRule{}, fmt.Errorf("unknown gear aggregate %q", aggregate)
+	}
+	return rule, nil
+}
+
+func (r GearRule) isGear(symbol Symbol, numbers int) bool {
+	return strings.ContainsRune(string(r.Symbols), symbol.value) && numbers >= r.MinNumbers && numbers <= r.MaxNumbers
+}
+
+// Gears returns the value of every gear by location. A number next to
+// several gears counts for each of them.
+func (s *Schematic) Gears(rule GearRule) map[Point]int {
+	gears := make(map[Point]int)
+	for _, symbol := range s.Symbols {
+		numbers := s.NumbersNextTo(symbol.location)
+		if !rule.isGear(symbol, len(numbers)) {
+			continue
+		}
+		values := make([]int, len(numbers))
+		for i, number := range numbers {
+			values[i] = number.Value
+		}
+		gears[symbol.location] = rule.Aggregate(values)
+	}
+	return gears
+}
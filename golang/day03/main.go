@@ -2,9 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"unicode"
+
+	"aoc/run"
 )
 
 type Point struct {
@@ -17,23 +25,63 @@ type Symbol struct {
 }
 
 type Number struct {
-	Value   int
+	Value int
+	// Start is the first digit, Length the number of digits.
+	Start   Point
+	Length  int
 	Symbols []Symbol
 }
 
-func padLine(line []rune, padding rune) []rune {
-	return append(append([]rune{padding}, line...), padding)
+func (n Number) covers(p Point) bool {
+	return p.y == n.Start.y && p.x >= n.Start.x && p.x < n.Start.x+n.Length
 }
 
-func filledLine(width int, fill rune) []rune {
-	line := make([]rune, width)
-	for i := 0; i < width; i++ {
-		line[i] = fill
+var adjacency = flag.String("adjacency", "8", "cells next to a number: 4 (no diagonals), 8 or radius=N")
+
+// adjacencyOffsets returns the offsets of the cells next to a single cell.
+func adjacencyOffsets(mode string) ([]Point, error) {
+	switch mode {
+	case "4":
+		return []Point{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}, nil
+	case "8":
+		return adjacencyOffsets("radius=1")
+	}
+	radius, err := strconv.Atoi(strings.TrimPrefix(mode, "radius="))
+	if !strings.HasPrefix(mode, "radius=") || err != nil || radius < 1 {
+		return nil, fmt.Errorf("invalid adjacency %q", mode)
 	}
-	return line
+	offsets := make([]Point, 0)
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x != 0 || y != 0 {
+				offsets = append(offsets, Point{x, y})
+			}
+		}
+	}
+	return offsets, nil
 }
 
-func loadBoard(filename string) ([]Number, error) {
+// Board holds the rows as read, rows may differ in length. Cells outside a
+// row are empty.
+type Board [][]rune
+
+func (b Board) at(p Point) rune {
+	if p.y < 0 || p.y >= len(b) || p.x < 0 || p.x >= len(b[p.y]) {
+		return '.'
+	}
+	return b[p.y][p.x]
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isSymbol is true for everything but digits, dots and whitespace like tabs.
+func isSymbol(r rune) bool {
+	return r != '.' && !isDigit(r) && !unicode.IsSpace(r)
+}
+
+func loadBoard(filename string, offsets []Point) (*Schematic, error) {
 	data, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -45,109 +93,123 @@ func loadBoard(filename string) ([]Number, error) {
 		}
 	}(data)
 	scanner := bufio.NewScanner(data)
-	numbers := make([]Number, 0)
-	paddedBoard := make([][]rune, 0)
-	// get first line to get width
-	scanner.Scan()
-	firstLine := scanner.Text()
-	width := len(firstLine)
-	paddedBoard = append(paddedBoard, filledLine(width, '.'))
-	paddedBoard = append(paddedBoard, padLine([]rune(firstLine), '.'))
+	schematic := &Schematic{}
+	board := make(Board, 0)
 	for scanner.Scan() {
-		line := scanner.Text()
-		paddedBoard = append(paddedBoard, padLine([]rune(line), '.'))
+		board = append(board, []rune(scanner.Text()))
 	}
-	paddedBoard = append(paddedBoard, filledLine(width, '.'))
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	// find numbers
-	for y, line := range paddedBoard {
-		numberInProgress := false
-		var currentNumber []rune
-		var start Point
-		for x, char := range line {
-			if unicode.IsDigit(char) {
-				if numberInProgress {
-					currentNumber = append(currentNumber, char)
-				} else {
-					numberInProgress = true
-					currentNumber = make([]rune, 0)
-					currentNumber = append(currentNumber, char)
-					start = Point{x: x - 1, y: y - 1}
-				}
-			} else {
-				if numberInProgress {
-					parsedNumber, err := strconv.Atoi(string(currentNumber))
-					if err != nil {
-						return nil, err
-					}
-					numbers = append(numbers, Number{Value: parsedNumber, Symbols: getSymbols(paddedBoard, start, Point{x, y + 1})})
-					numberInProgress = false
-				}
+	for y, line := range board {
+		for x := 0; x < len(line); x++ {
+			if isSymbol(line[x]) {
+				schematic.Symbols = append(schematic.Symbols, Symbol{value: line[x], location: Point{x: x, y: y}})
+			}
+			if !isDigit(line[x]) {
+				continue
+			}
+			start := Point{x: x, y: y}
+			for x < len(line) && isDigit(line[x]) {
+				x++
+			}
+			parsedNumber, err := strconv.Atoi(string(line[start.x:x]))
+			if err != nil {
+				return nil, err
 			}
+			number := Number{Value: parsedNumber, Start: start, Length: x - start.x}
+			number.Symbols = getSymbols(board, number, offsets)
+			schematic.Numbers = append(schematic.Numbers, number)
+			x--
 		}
 	}
-	return numbers, nil
+	schematic.link()
+	return schematic, nil
 }
 
-func getSymbols(board [][]rune, start Point, end Point) []Symbol {
-	symbols := make([]Symbol, 0)
-	for i := start.x; i <= end.x; i++ {
-		if board[start.y][i] != '.' && !unicode.IsDigit(board[start.y][i]) {
-			symbols = append(symbols, Symbol{value: board[start.y][i], location: Point{x: i, y: start.y}})
-		}
-		if board[end.y][i] != '.' && !unicode.IsDigit(board[end.y][i]) {
-			symbols = append(symbols, Symbol{value: board[end.y][i], location: Point{x: i, y: end.y}})
-		}
+func loadInput() (*Schematic, error) {
+	offsets, err := adjacencyOffsets(*adjacency)
+	if err != nil {
+		return nil, err
 	}
-	if board[start.y+1][start.x] != '.' && !unicode.IsDigit(board[start.y+1][start.x]) {
-		symbols = append(symbols, Symbol{value: board[start.y+1][start.x], location: Point{x: start.x, y: start.y + 1}})
+	return loadBoard("input.txt", offsets)
+}
+
+// getSymbols finds the symbols next to any digit of the number, ordered by
+// row and column.
+func getSymbols(board Board, number Number, offsets []Point) []Symbol {
+	seen := make(map[Point]bool)
+	for x := number.Start.x; x < number.Start.x+number.Length; x++ {
+		for _, offset := range offsets {
+			p := Point{x: x + offset.x, y: number.Start.y + offset.y}
+			if !number.covers(p) && isSymbol(board.at(p)) {
+				seen[p] = true
+			}
+		}
 	}
-	if board[start.y+1][end.x] != '.' && !unicode.IsDigit(board[start.y+1][end.x]) {
-		symbols = append(symbols, Symbol{value: board[start.y+1][end.x], location: Point{x: end.x, y: start.y + 1}})
+	symbols := make([]Symbol, 0, len(seen))
+	for p := range seen {
+		symbols = append(symbols, Symbol{value: board.at(p), location: p})
 	}
+	slices.SortFunc(symbols, func(a, b Symbol) int {
+		if a.location.y != b.location.y {
+			return a.location.y - b.location.y
+		}
+		return a.location.x - b.location.x
+	})
 	return symbols
 }
 
-func solutionPart1() {
-	board, err := loadBoard("input.txt")
+func solutionPart1(ctx context.Context) error {
+	board, err := loadInput()
 	if err != nil {
 		panic(err)
 	}
 	partNumbersSum := 0
-	for _, number := range board {
+	for _, number := range board.Numbers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if len(number.Symbols) > 0 {
 			partNumbersSum += number.Value
 		}
 	}
 	println(partNumbersSum)
+	return nil
 }
 
-func solutionPart2() {
-	board, err := loadBoard("input.txt")
+func solutionPart2(ctx context.Context) error {
+	board, err := loadInput()
 	if err != nil {
 		panic(err)
 	}
-	gearNumbers := make(map[Point][]int)
-	for _, number := range board {
-		for _, symbol := range number.Symbols {
-			if symbol.value == '*' {
-				gearNumbers[symbol.location] = append(gearNumbers[symbol.location], number.Value)
-				break
-			}
-		}
+	rule, err := parseGearRule(*gearSymbols, *gearCount, *gearAggregate)
+	if err != nil {
+		return err
 	}
 	gearRatioSum := 0
-	for _, gearNumber := range gearNumbers {
-		if len(gearNumber) == 2 {
-			gearRatioSum += gearNumber[0] * gearNumber[1]
+	for _, value := range board.Gears(rule) {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		gearRatioSum += value
 	}
 	println(gearRatioSum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if *jsonOutput || *query != "" {
+		if err := inspect(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := run.Part(3, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(3, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
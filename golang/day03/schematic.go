@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonOutput = flag.Bool("json", false, "print the schematic graph as JSON instead of solving")
+	query      = flag.String("query", "", "print the neighbours of the number or symbol at x,y instead of solving")
+)
+
+// Schematic is a bipartite graph of the numbers and the symbols, with an
+// edge between every number and every symbol next to it.
+type Schematic struct {
+	Numbers []Number
+	Symbols []Symbol
+	// symbolNumbers holds the indices of the numbers next to a symbol.
+	symbolNumbers map[Point][]int
+}
+
+func (s *Schematic) link() {
+	s.symbolNumbers = make(map[Point][]int)
+	for i, number := range s.Numbers {
+		for _, symbol := range number.Symbols {
+			s.symbolNumbers[symbol.location] = append(s.symbolNumbers[symbol.location], i)
+		}
+	}
+}
+
+func (s *Schematic) SymbolAt(p Point) (Symbol, bool) {
+	for _, symbol := range s.Symbols {
+		if symbol.location == p {
+			return symbol, true
+		}
+	}
+	return Symbol{}, false
+}
+
+func (s *Schematic) NumberAt(p Point) (Number, bool) {
+	for _, number := range s.Numbers {
+		if number.covers(p) {
+			return number, true
+		}
+	}
+	return Number{}, false
+}
+
+// NumbersNextTo returns the numbers adjacent to the symbol at p.
+func (s *Schematic) NumbersNextTo(p Point) []Number {
+	numbers := make([]Number, 0)
+	for _, i := range s.symbolNumbers[p] {
+		numbers = append(numbers, s.Numbers[i])
+	}
+	return numbers
+}
+
+// SymbolsNextTo returns the symbols adjacent to the number with a digit at p.
+func (s *Schematic) SymbolsNextTo(p Point) []Symbol {
+	number, ok := s.NumberAt(p)
+	if !ok {
+		return nil
+	}
+	return number.Symbols
+}
+
+type jsonNumber struct {
+	ID     int `json:"id"`
+	Value  int `json:"value"`
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Length int `json:"length"`
+}
+
+type jsonSymbol struct {
+	ID    int    `json:"id"`
+	Value string `json:"value"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+}
+
+type jsonEdge struct {
+	Number int `json:"number"`
+	Symbol int `json:"symbol"`
+}
+
+type jsonSchematic struct {
+	Numbers []jsonNumber `json:"numbers"`
+	Symbols []jsonSymbol `json:"symbols"`
+	Edges   []jsonEdge   `json:"edges"`
+}
+
+func (s *Schematic) MarshalJSON() ([]byte, error) {
+	out := jsonSchematic{Numbers: []jsonNumber{}, Symbols: []jsonSymbol{}, Edges: []jsonEdge{}}
+	symbolIDs := make(map[Point]int)
+	for i, symbol := range s.Symbols {
+		symbolIDs[symbol.location] = i
+		out.Symbols = append(out.Symbols, jsonSymbol{i, string(symbol.value), symbol.location.x, symbol.location.y})
+	}
+	for i, number := range s.Numbers {
+		out.Numbers = append(out.Numbers, jsonNumber{i, number.Value, number.Start.x, number.Start.y, number.Length})
+		for _, symbol := range number.Symbols {
+			out.Edges = append(out.Edges, jsonEdge{i, symbolIDs[symbol.location]})
+		}
+	}
+	return json.Marshal(out)
+}
+
+func parsePoint(s string) (Point, error) {
+	x, y, ok := strings.Cut(s, ",")
+	if !ok {
+		return Point{}, fmt.Errorf("invalid point %q, expected x,y", s)
+	}
+	px, err := strconv.Atoi(strings.TrimSpace(x))
+	if err != nil {
+		return Point{}, err
+	}
+	py, err := strconv.Atoi(strings.TrimSpace(y))
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{x: px, y: py}, nil
+}
+
+func inspect(w io.Writer) error {
+	schematic, err := loadInput()
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(schematic)
+	}
+	p, err := parsePoint(*query)
+	if err != nil {
+		return err
+	}
+	if symbol, ok := schematic.SymbolAt(p); ok {
+		fmt.Fprintf(w, "symbol %c at %d,%d\n", symbol.value, p.x, p.y)
+		for _, number := range schematic.NumbersNextTo(p) {
+			fmt.Fprintf(w, "  number %d at %d,%d\n", number.Value, number.Start.x, number.Start.y)
+		}
+		return nil
+	}
+	if number, ok := schematic.NumberAt(p); ok {
+		fmt.Fprintf(w, "number %d at %d,%d\n", number.Value, number.Start.x, number.Start.y)
+		for _, symbol := range schematic.SymbolsNextTo(p) {
+			fmt.Fprintf(w, "  symbol %c at %d,%d\n", symbol.value, symbol.location.x, symbol.location.y)
+		}
+		return nil
+	}
+	return fmt.Errorf("nothing at %d,%d", p.x, p.y)
+}
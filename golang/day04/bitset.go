@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math/bits"
+)
+
+// Bitset is a set of small non-negative integers, one bit per number.
+type Bitset []uint64
+
+func (b *Bitset) Insert(n int) {
+	word := n / 64
+	for len(*b) <= word {
+		*b = append(*b, 0)
+	}
+	(*b)[word] |= 1 << (n % 64)
+}
+
+func (b Bitset) Contains(n int) bool {
+	word := n / 64
+	return n >= 0 && word < len(b) && b[word]&(1<<(n%64)) != 0
+}
+
+func (b Bitset) Size() int {
+	size := 0
+	for _, word := range b {
+		size += bits.OnesCount64(word)
+	}
+	return size
+}
+
+// IntersectionSize counts the numbers in both sets without building the
+// intersection.
+func (b Bitset) IntersectionSize(other Bitset) int {
+	size := 0
+	for i := 0; i < min(len(b), len(other)); i++ {
+		size += bits.OnesCount64(b[i] & other[i])
+	}
+	return size
+}
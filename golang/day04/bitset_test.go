@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type numbers struct {
+	winning, mine []int
+}
+
+// generateCards returns cards with 10 winning and 25 own numbers from 0 to
+// 98, like the puzzle input.
+func generateCards(count int) []numbers {
+	random := rand.New(rand.NewSource(4))
+	cards := make([]numbers, count)
+	for i := range cards {
+		cards[i] = numbers{random.Perm(99)[:10], random.Perm(99)[:25]}
+	}
+	return cards
+}
+
+// mapMatches counts the matches with map based sets, the way the cards were
+// matched before the bitset.
+func mapMatches(card numbers) int {
+	winning := make(map[int]struct{}, len(card.winning))
+	for _, number := range card.winning {
+		winning[number] = struct{}{}
+	}
+	mine := make(map[int]struct{}, len(card.mine))
+	for _, number := range card.mine {
+		mine[number] = struct{}{}
+	}
+	matches := 0
+	for number := range mine {
+		if _, ok := winning[number]; ok {
+			matches++
+		}
+	}
+	return matches
+}
+
+func bitmapMatches(card numbers) int {
+	c := Card{}
+	for _, number := range card.winning {
+		c.WinningNumbers.Insert(number)
+	}
+	for _, number := range card.mine {
+		c.MyNumbers.Insert(number)
+	}
+	return c.Matches()
+}
+
+func TestBitsetMatchesMap(t *testing.T) {
+	for i, card := range generateCards(1000) {
+		if got, want := bitmapMatches(card), mapMatches(card); got != want {
+			t.Fatalf("card %d: bitset found %d matches, map %d", i, got, want)
+		}
+	}
+}
+
+func TestParseNumberList(t *testing.T) {
+	tests := []struct {
+		list    string
+		size    int
+		wantErr bool
+	}{
+		{list: "41 48 83 86 17", size: 5},
+		{list: " 1  2  2 ", size: 2},
+		{list: "", size: 0},
+		{list: "65535", size: 1},
+		{list: "65536", wantErr: true},
+		{list: "1000000000", wantErr: true},
+		{list: "-1", wantErr: true},
+		{list: "x", wantErr: true},
+	}
+	for _, test := range tests {
+		numbers, err := parseNumberList(test.list)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseNumberList(%q) = %v, want an error", test.list, numbers)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseNumberList(%q): %v", test.list, err)
+		} else if numbers.Size() != test.size {
+			t.Errorf("parseNumberList(%q) has %d numbers, want %d", test.list, numbers.Size(), test.size)
+		}
+	}
+}
+
+func BenchmarkMapMatches(b *testing.B) {
+	cards := generateCards(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mapMatches(cards[i%len(cards)])
+	}
+}
+
+func BenchmarkBitmapMatches(b *testing.B) {
+	cards := generateCards(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bitmapMatches(cards[i%len(cards)])
+	}
+}
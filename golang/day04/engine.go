@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Scoring turns the number of matches of a card into points.
+type Scoring func(matches int) int
+
+var scorings = map[string]Scoring{
+	// double is the puzzle's rule: one point for the first match, doubled for
+	// every further one.
+	"double": func(matches int) int {
+		if matches == 0 {
+			return 0
+		}
+		return pow(2, matches-1)
+	},
+	"linear": func(matches int) int {
+		return matches
+	},
+	"square": func(matches int) int {
+		return matches * matches
+	},
+}
+
+// CopyRule returns the indices of the cards a card wins a copy of. They may
+// be outside the pile, the overflow policy decides what happens then.
+type CopyRule func(index, matches int) []int
+
+var copyRules = map[string]CopyRule{
+	// next is the puzzle's rule: one copy of each of the following cards.
+	"next": func(index, matches int) []int {
+		targets := make([]int, matches)
+		for i := range targets {
+			targets[i] = index + i + 1
+		}
+		return targets
+	},
+	"previous": func(index, matches int) []int {
+		targets := make([]int, matches)
+		for i := range targets {
+			targets[i] = index - i - 1
+		}
+		return targets
+	},
+	"every-other": func(index, matches int) []int {
+		targets := make([]int, matches)
+		for i := range targets {
+			targets[i] = index + 2*(i+1)
+		}
+		return targets
+	},
+}
+
+type Overflow int
+
+const (
+	// Clamp gives copies outside the pile to the first or last card.
+	Clamp Overflow = iota
+	// Wrap continues counting at the other end of the pile.
+	Wrap
+	// Error stops with an OverflowError.
+	Error
+)
+
+var overflows = map[string]Overflow{
+	"clamp": Clamp,
+	"wrap":  Wrap,
+	"error": Error,
+}
+
+type OverflowError struct {
+	Card, Target, Cards int
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("card %d wins a copy of card %d, but there are only %d cards", e.Card+1, e.Target+1, e.Cards)
+}
+
+type Engine struct {
+	Scoring  Scoring
+	Copies   CopyRule
+	Overflow Overflow
+}
+
+func newEngine(scoring, copies, overflow string) (Engine, error) {
+	engine := Engine{}
+	var ok bool
+	if engine.Scoring, ok = scorings[scoring]; !ok {
+		return Engine{}, fmt.Errorf("unknown scoring %q", scoring)
+	}
+	if engine.Copies, ok = copyRules[copies]; !ok {
+		return Engine{}, fmt.Errorf("unknown copy rule %q", copies)
+	}
+	if engine.Overflow, ok = overflows[overflow]; !ok {
+		return Engine{}, fmt.Errorf("unknown overflow policy %q", overflow)
+	}
+	return engine, nil
+}
+
+func (e Engine) Score(cards []Card) int {
+	score := 0
+	for _, card := range cards {
+		score += e.Scoring(card.Matches())
+	}
+	return score
+}
+
+func (e Engine) target(card, target, cards int) (int, error) {
+	if target >= 0 && target < cards {
+		return target, nil
+	}
+	switch e.Overflow {
+	case Clamp:
+		return min(max(target, 0), cards-1), nil
+	case Wrap:
+		return (target%cards + cards) % cards, nil
+	}
+	return 0, &OverflowError{Card: card, Target: target, Cards: cards}
+}
+
+// CopyCounts returns how many copies of each card there are in the end,
+// starting with one of each. The cards are processed in order, so copies won
+// for a card that was already processed don't win any further cards.
+func (e Engine) CopyCounts(cards []Card) ([]int, error) {
+	pile := make([]int, len(cards))
+	for i := range pile {
+		pile[i] = 1
+	}
+	for index, card := range cards {
+		// a card can win copies of itself, which must not add up
+		count := pile[index]
+		for _, target := range e.Copies(index, card.Matches()) {
+			target, err := e.target(index, target, len(cards))
+			if err != nil {
+				return nil, err
+			}
+			pile[target] += count
+		}
+	}
+	return pile, nil
+}
@@ -2,4 +2,6 @@ module day04
 
 go 1.21.5
 
-require github.com/hashicorp/go-set v0.1.14 // indirect
+require aoc v0.0.0
+
+replace aoc => ../aoc
@@ -2,19 +2,33 @@ package main
 
 import (
 	"bufio"
-	"github.com/hashicorp/go-set"
+	"context"
+	"flag"
+	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 type Card struct {
-	WinningNumbers *set.Set[int]
-	MyNumbers      *set.Set[int]
+	WinningNumbers Bitset
+	MyNumbers      Bitset
+}
+
+// Matches counts my numbers that are winning numbers.
+func (c Card) Matches() int {
+	return c.MyNumbers.IntersectionSize(c.WinningNumbers)
 }
 
-func parseNumberList(list string) (*set.Set[int], error) {
-	numbers := set.New[int](0)
+// maxCardNumber bounds the numbers on a card, so a stray large number can't
+// blow up the bitsets. The puzzle only uses numbers below 100.
+const maxCardNumber = 1<<16 - 1
+
+func parseNumberList(list string) (Bitset, error) {
+	numbers := Bitset{}
 	for _, number := range strings.Split(list, " ") {
 		if number == "" {
 			continue
@@ -23,6 +37,12 @@ func parseNumberList(list string) (*set.Set[int], error) {
 		if err != nil {
 			return nil, err
 		}
+		if numberInt < 0 {
+			return nil, fmt.Errorf("negative card number %d", numberInt)
+		}
+		if numberInt > maxCardNumber {
+			return nil, fmt.Errorf("card number %d is larger than %d", numberInt, maxCardNumber)
+		}
 		numbers.Insert(numberInt)
 	}
 	return numbers, nil
@@ -67,44 +87,62 @@ func pow(a int, b int) int {
 	return result
 }
 
-func solutionPart1() {
+var (
+	scoring    = flag.String("scoring", "double", "points for the matches of a card: double, linear or square")
+	copies     = flag.String("copies", "next", "cards a card wins copies of: next, previous or every-other")
+	overflow   = flag.String("overflow", "error", "copies past the end of the pile: clamp, wrap or error")
+	showCopies = flag.Bool("show-copies", false, "print the number of copies of every card in part 2")
+)
+
+func solutionPart1(ctx context.Context) error {
 	cards, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
-	score := 0
-	for _, card := range cards {
-		matches := card.MyNumbers.Intersect(card.WinningNumbers).Size()
-		if matches > 0 {
-			score += pow(2, matches-1)
-		}
+	engine, err := newEngine(*scoring, *copies, *overflow)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-	println(score)
+	println(engine.Score(cards))
+	return nil
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	cards, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
-	pile := make([]int, len(cards))
-	for i := range pile {
-		pile[i] = 1
+	engine, err := newEngine(*scoring, *copies, *overflow)
+	if err != nil {
+		return err
 	}
-	for index, card := range cards {
-		matches := card.MyNumbers.Intersect(card.WinningNumbers).Size()
-		for i := 0; i < matches; i++ {
-			pile[index+i+1] += pile[index]
-		}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	pile, err := engine.CopyCounts(cards)
+	if err != nil {
+		return err
 	}
 	sum := 0
-	for _, count := range pile {
+	for index, count := range pile {
+		if *showCopies {
+			fmt.Printf("Card %d: %d\n", index+1, count)
+		}
 		sum += count
 	}
 	println(sum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(4, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(4, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	from = flag.String("from", "seed", "category the seed numbers are read as")
+	to   = flag.String("to", "location", "category to map the seed numbers to")
+)
+
+// entry is a line of a map as read, destination start, source start and
+// length once it is validated.
+type entry struct {
+	line    int
+	numbers []int64
+}
+
+// Mapping is one map of the almanac, e.g. "seed-to-soil map:".
+type Mapping struct {
+	From, To  string
+	Intervals []*OffsetInterval
+	entries   []entry
+}
+
+type Almanac struct {
+	Seeds     []int64
+	SeedsLine int
+	// Mappings holds the maps by their source category.
+	Mappings map[string]*Mapping
+}
+
+var headerRegex = regexp.MustCompile(`^(\w+)-to-(\w+) map:$`)
+
+func parseNumbers(line string) ([]int64, error) {
+	numbers := make([]int64, 0)
+	for _, num := range strings.Fields(line) {
+		parsedNum, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, parsedNum)
+	}
+	return numbers, nil
+}
+
+// loadAlmanac reads and validates an almanac and builds its maps. The seeds
+// are only checked as seed numbers, see SeedPairProblems for part 2.
+func loadAlmanac(filename string) (*Almanac, error) {
+	almanac, err := readAlmanac(filename)
+	if err != nil {
+		return nil, err
+	}
+	if problems := almanac.Validate(); len(problems) > 0 {
+		return nil, &ValidationError{problems}
+	}
+	almanac.build()
+	return almanac, nil
+}
+
+// readAlmanac parses an almanac without checking the numbers.
+func readAlmanac(filename string) (*Almanac, error) {
+	data, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer data.Close()
+	scanner := bufio.NewScanner(data)
+	almanac := &Almanac{Mappings: make(map[string]*Mapping)}
+	var current *Mapping
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			current = nil
+		case strings.HasPrefix(line, "seeds:"):
+			almanac.Seeds, err = parseNumbers(strings.TrimPrefix(line, "seeds:"))
+			almanac.SeedsLine = lineNumber
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		case headerRegex.MatchString(line):
+			matches := headerRegex.FindStringSubmatch(line)
+			if _, ok := almanac.Mappings[matches[1]]; ok {
+				return nil, fmt.Errorf("line %d: second map from %s", lineNumber, matches[1])
+			}
+			current = &Mapping{From: matches[1], To: matches[2]}
+			almanac.Mappings[current.From] = current
+		case current != nil:
+			numbers, err := parseNumbers(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			current.entries = append(current.entries, entry{line: lineNumber, numbers: numbers})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", lineNumber, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return almanac, nil
+}
+
+// build turns the validated map lines into intervals covering all numbers.
+func (a *Almanac) build() {
+	for _, mapping := range a.Mappings {
+		for _, entry := range mapping.entries {
+			numbers := entry.numbers
+			mapping.Intervals = append(mapping.Intervals,
+				NewOffsetInterval(
+					numbers[1],
+					numbers[1]+numbers[2]-1,
+					numbers[0]-numbers[1],
+				),
+			)
+		}
+		if len(mapping.Intervals) > 0 {
+			mapping.Intervals = fillIntervalsGaps(mapping.Intervals)
+		} else {
+			mapping.Intervals = []*OffsetInterval{NewRightOpenOffsetInterval(0, 0)}
+		}
+	}
+}
+
+// Chain returns the maps that lead from one category to another in order.
+func (a *Almanac) Chain(from, to string) ([][]*OffsetInterval, error) {
+	maps := make([][]*OffsetInterval, 0)
+	visited := map[string]bool{from: true}
+	path := []string{from}
+	for category := from; category != to; {
+		mapping, ok := a.Mappings[category]
+		if !ok {
+			return nil, fmt.Errorf("no map from %s, the chain %s does not reach %s", category, strings.Join(path, "-"), to)
+		}
+		category = mapping.To
+		path = append(path, category)
+		if visited[category] {
+			return nil, fmt.Errorf("the chain %s is cyclic", strings.Join(path, "-"))
+		}
+		visited[category] = true
+		maps = append(maps, mapping.Intervals)
+	}
+	return maps, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Piece maps every number from Start up to the start of the next piece by
+// adding Offset.
+type Piece struct {
+	Start  int64
+	Offset int64
+}
+
+// Piecewise is a piecewise linear function over all int64, given by its
+// pieces sorted by start. The first piece starts at math.MinInt64.
+type Piecewise []Piece
+
+// addSaturated adds without wrapping around at the ends of int64.
+func addSaturated(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+// layerPiecewise turns one gap filled map of the almanac into a piecewise
+// function. Numbers below the map keep their value.
+func layerPiecewise(layer []*OffsetInterval) Piecewise {
+	f := Piecewise{{Start: math.MinInt64}}
+	for _, interval := range layer {
+		start := int64(math.MinInt64)
+		if interval.start != nil {
+			start = *interval.start
+		}
+		if start == math.MinInt64 {
+			f[0].Offset = interval.offset
+			continue
+		}
+		f = append(f, Piece{Start: start, Offset: interval.offset})
+	}
+	return f.merged()
+}
+
+// merged drops pieces with the same offset as their predecessor.
+func (f Piecewise) merged() Piecewise {
+	result := Piecewise{}
+	for _, piece := range f {
+		if len(result) > 0 && result[len(result)-1].Offset == piece.Offset {
+			continue
+		}
+		result = append(result, piece)
+	}
+	return result
+}
+
+// end returns the last number of the piece at index i.
+func (f Piecewise) end(i int) int64 {
+	if i == len(f)-1 {
+		return math.MaxInt64
+	}
+	return f[i+1].Start - 1
+}
+
+// index returns the index of the piece containing x in O(log n).
+func (f Piecewise) index(x int64) int {
+	return sort.Search(len(f), func(i int) bool { return f[i].Start > x }) - 1
+}
+
+func (f Piecewise) Eval(x int64) int64 {
+	return x + f[f.index(x)].Offset
+}
+
+// Then returns the function that applies f first and g to the result.
+func (f Piecewise) Then(g Piecewise) Piecewise {
+	result := Piecewise{}
+	for i, piece := range f {
+		imageStart := addSaturated(piece.Start, piece.Offset)
+		imageEnd := addSaturated(f.end(i), piece.Offset)
+		for j := g.index(imageStart); j < len(g) && g[j].Start <= imageEnd; j++ {
+			start := piece.Start
+			if g[j].Start > imageStart {
+				start = g[j].Start - piece.Offset
+			}
+			result = append(result, Piece{Start: start, Offset: piece.Offset + g[j].Offset})
+		}
+	}
+	return result.merged()
+}
+
+// Compose turns the whole chain of maps into a single function.
+func Compose(maps [][]*OffsetInterval) Piecewise {
+	f := Piecewise{{Start: math.MinInt64}}
+	for _, layer := range maps {
+		f = f.Then(layerPiecewise(layer))
+	}
+	return f
+}
+
+// MinOfRange returns the smallest value of f from start to end. Every piece
+// is increasing, so only the first number of each piece in the range counts.
+// An empty range has no smallest value and yields math.MaxInt64.
+func (f Piecewise) MinOfRange(start, end int64) int64 {
+	lowest := int64(math.MaxInt64)
+	if start > end {
+		return lowest
+	}
+	for i := f.index(start); i < len(f) && f[i].Start <= end; i++ {
+		lowest = min(lowest, max(start, f[i].Start)+f[i].Offset)
+	}
+	return lowest
+}
+
+func (f Piecewise) String() string {
+	sb := strings.Builder{}
+	for i, piece := range f {
+		start, end := fmt.Sprint(piece.Start), fmt.Sprint(f.end(i))
+		if piece.Start == math.MinInt64 {
+			start = "-inf"
+		}
+		if i == len(f)-1 {
+			end = "inf"
+		}
+		sb.WriteString(fmt.Sprintf("%s..%s %+d\n", start, end, piece.Offset))
+	}
+	return sb.String()
+}
+
+func lowestLocationComposed(ctx context.Context, seeds []int64, maps [][]*OffsetInterval) (int64, error) {
+	f := Compose(maps)
+	lowest := int64(math.MaxInt64)
+	for _, seed := range seeds {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		lowest = min(lowest, f.Eval(seed))
+	}
+	return lowest, nil
+}
+
+func lowestLocationOfRangesComposed(ctx context.Context, seeds []int64, maps [][]*OffsetInterval) (int64, error) {
+	f := Compose(maps)
+	lowest := int64(math.MaxInt64)
+	for i := 0; i < len(seeds)-1; i += 2 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if seeds[i+1] <= 0 {
+			continue
+		}
+		lowest = min(lowest, f.MinOfRange(seeds[i], seeds[i]+seeds[i+1]-1))
+	}
+	return lowest, nil
+}
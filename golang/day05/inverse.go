@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Range is an inclusive range of numbers.
+type Range struct {
+	Start, End int64
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d..%d", r.Start, r.End)
+}
+
+// mergeRanges sorts the ranges and joins the ones that overlap or touch.
+func mergeRanges(ranges []Range) []Range {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	merged := make([]Range, 0, len(ranges))
+	for _, r := range ranges {
+		if len(merged) > 0 && r.Start <= addSaturated(merged[len(merged)-1].End, 1) {
+			merged[len(merged)-1].End = max(merged[len(merged)-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// invertLayer returns the numbers the layer maps into r.
+func invertLayer(layer []*OffsetInterval, r Range) []Range {
+	sources := make([]Range, 0)
+	for _, interval := range layer {
+		start, end := int64(math.MinInt64), int64(math.MaxInt64)
+		if interval.start != nil {
+			start = *interval.start
+		}
+		if interval.end != nil {
+			end = *interval.end
+		}
+		imageStart := max(addSaturated(start, interval.offset), r.Start)
+		imageEnd := min(addSaturated(end, interval.offset), r.End)
+		if imageStart <= imageEnd {
+			sources = append(sources, Range{imageStart - interval.offset, imageEnd - interval.offset})
+		}
+	}
+	return sources
+}
+
+// Preimage returns the seed ranges that end up in the location range.
+func Preimage(maps [][]*OffsetInterval, location Range) []Range {
+	ranges := []Range{location}
+	for i := len(maps) - 1; i >= 0; i-- {
+		sources := make([]Range, 0)
+		for _, r := range ranges {
+			sources = append(sources, invertLayer(maps[i], r)...)
+		}
+		ranges = mergeRanges(sources)
+	}
+	return ranges
+}
+
+// seedRanges reads the seeds as start and length pairs, skipping empty
+// ranges.
+func seedRanges(seeds []int64) []Range {
+	ranges := make([]Range, 0)
+	for i := 0; i < len(seeds)-1; i += 2 {
+		if seeds[i+1] <= 0 {
+			continue
+		}
+		ranges = append(ranges, Range{seeds[i], seeds[i] + seeds[i+1] - 1})
+	}
+	return ranges
+}
+
+func intersects(a, b []Range) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Start <= y.End && y.Start <= x.End {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lowestLocationOfRangesInverse scans the locations upward until one maps
+// back into a seed range. It checks whole blocks of locations at once,
+// doubling the block until it hits a seed and then halving it again.
+func lowestLocationOfRangesInverse(ctx context.Context, seeds []int64, maps [][]*OffsetInterval) (int64, error) {
+	seedsOf := seedRanges(seeds)
+	hits := func(end int64) bool {
+		return intersects(Preimage(maps, Range{0, end}), seedsOf)
+	}
+	if len(seedsOf) == 0 || !hits(math.MaxInt64) {
+		return 0, fmt.Errorf("no location maps back to a seed")
+	}
+	high := int64(1)
+	for !hits(high - 1) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		high = addSaturated(high, high)
+	}
+	low := int64(0)
+	for low < high-1 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		middle := low + (high-low)/2
+		if hits(middle - 1) {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+	return low, nil
+}
+
+func printSeedsFor(location int64) error {
+	seeds, maps, err := loadData("input.txt")
+	if err != nil {
+		return err
+	}
+	ranges := Preimage(maps, Range{location, location})
+	fmt.Printf("Location %d comes from %v\n", location, ranges)
+	for _, r := range ranges {
+		for _, seed := range seedRanges(seeds) {
+			if r.Start <= seed.End && seed.Start <= r.End {
+				fmt.Printf("seed %d is in the seed range %v\n", max(r.Start, seed.Start), seed)
+			}
+		}
+	}
+	return nil
+}
@@ -1,12 +1,13 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"os"
+	"log"
 	"sort"
-	"strconv"
-	"strings"
+
+	"aoc/run"
 )
 
 type Interval struct {
@@ -128,69 +129,26 @@ func fillIntervalsGaps(intervals []*OffsetInterval) []*OffsetInterval {
 }
 
 func loadData(filename string) ([]int64, [][]*OffsetInterval, error) {
-	data, err := os.Open(filename)
+	almanac, err := loadAlmanac(filename)
 	if err != nil {
 		return nil, nil, err
 	}
-	scanner := bufio.NewScanner(data)
-	defer func(data *os.File) {
-		err := data.Close()
-		if err != nil {
-
-		}
-	}(data)
-	intervals := make([][]*OffsetInterval, 0)
-	scanner.Scan()
-	seedString := strings.TrimSpace(strings.Split(scanner.Text(), ":")[1])
-	seeds := make([]int64, 0)
-	for _, seed := range strings.Split(seedString, " ") {
-		parsedSeed, err := strconv.ParseInt(seed, 10, 64)
-		if err != nil {
-			return nil, nil, err
-		}
-		seeds = append(seeds, parsedSeed)
-	}
-	scanner.Scan()
-	for scanner.Scan() {
-		scanner.Scan()
-		line := scanner.Text()
-		currentMap := make([]*OffsetInterval, 0)
-		for line != "" {
-			numbers := make([]int64, 0)
-			for _, num := range strings.Split(line, " ") {
-				parsedNum, err := strconv.ParseInt(num, 10, 64)
-				if err != nil {
-					return nil, nil, err
-				}
-				numbers = append(numbers, parsedNum)
-			}
-			currentMap = append(currentMap,
-				NewOffsetInterval(
-					numbers[1],
-					numbers[1]+numbers[2]-1,
-					numbers[0]-numbers[1],
-				),
-			)
-			scanner.Scan()
-			line = scanner.Text()
-		}
-		currentMap = fillIntervalsGaps(currentMap)
-		intervals = append(intervals, currentMap)
+	maps, err := almanac.Chain(*from, *to)
+	if err != nil {
+		return nil, nil, err
 	}
-	return seeds, intervals, nil
+	return almanac.Seeds, maps, nil
 }
 
-func solutionPart1() {
-	seeds, maps, err := loadData("input.txt")
-	if err != nil {
-		fmt.Println("Error loading data:", err)
-		return
-	}
+func lowestLocation(ctx context.Context, seeds []int64, maps [][]*OffsetInterval) (int64, error) {
 	sort.Slice(seeds, func(i, j int) bool {
 		return seeds[i] < seeds[j]
 	})
 	currentSeeds := seeds
 	for _, seedMap := range maps {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		currentMapIndex := 0
 		nextSeeds := make([]int64, 0)
 		for _, seed := range currentSeeds {
@@ -204,26 +162,42 @@ func solutionPart1() {
 		})
 		currentSeeds = nextSeeds
 	}
-	println(currentSeeds[0])
+	return currentSeeds[0], nil
 }
 
-func solutionPart2() {
+func solutionPart1(ctx context.Context) error {
 	seeds, maps, err := loadData("input.txt")
 	if err != nil {
-		fmt.Println("Error loading data:", err)
-		return
+		return err
+	}
+	location, err := lowestLocationComposed(ctx, seeds, maps)
+	if err != nil {
+		return err
 	}
+	println(location)
+	return nil
+}
+
+func lowestLocationOfRanges(ctx context.Context, seeds []int64, maps [][]*OffsetInterval) (int64, error) {
 	currentSeedIntervals := make([]*Interval, 0)
 	for i := 0; i < len(seeds)-1; i += 2 {
+		if seeds[i+1] <= 0 {
+			continue
+		}
 		currentSeedIntervals = append(currentSeedIntervals, NewInterval(seeds[i], seeds[i]+seeds[i+1]-1))
 	}
 	sort.Slice(currentSeedIntervals, func(i, j int) bool {
 		return *currentSeedIntervals[i].start < *currentSeedIntervals[j].start
 	})
 	for _, seedMap := range maps {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		nextSeedIntervals := make([]*Interval, 0)
-		currentSeedMapIndex := 0
 		for _, interval := range currentSeedIntervals {
+			// mapped intervals can overlap, so each one starts its search at the
+			// beginning of the map
+			currentSeedMapIndex := 0
 			for !seedMap[currentSeedMapIndex].Contains(*interval.start) {
 				currentSeedMapIndex += 1
 			}
@@ -243,10 +217,66 @@ func solutionPart2() {
 			return *currentSeedIntervals[i].start < *currentSeedIntervals[j].start
 		})
 	}
-	println(*currentSeedIntervals[0].start)
+	return *currentSeedIntervals[0].start, nil
+}
+
+func solutionPart2(ctx context.Context) error {
+	almanac, err := loadAlmanac("input.txt")
+	if err != nil {
+		return err
+	}
+	if problems := almanac.SeedPairProblems(); len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	maps, err := almanac.Chain(*from, *to)
+	if err != nil {
+		return err
+	}
+	seeds := almanac.Seeds
+	location, err := lowestLocationOfRangesComposed(ctx, seeds, maps)
+	if err != nil {
+		return err
+	}
+	println(location)
+	return nil
 }
 
+var (
+	reference = flag.Bool("reference", false, "cross-check against the brute-force reference solver")
+	table     = flag.Bool("table", false, "print the composed seed to location function instead of solving")
+	seedFor   = flag.Int64("seed-for", -1, "print the seeds that map to this location instead of solving")
+)
+
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if *reference {
+		runReference()
+		return
+	}
+	if *validate {
+		if err := printValidation(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *seedFor >= 0 {
+		if err := printSeedsFor(*seedFor); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *table {
+		_, maps, err := loadData("input.txt")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(Compose(maps))
+		return
+	}
+	if err := run.Part(5, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(5, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// referenceLocation maps a single seed through every layer by scanning all
+// intervals of the layer, without relying on their order.
+func referenceLocation(seed int64, maps [][]*OffsetInterval) int64 {
+	current := seed
+	for _, seedMap := range maps {
+		for _, interval := range seedMap {
+			if interval.Contains(current) {
+				current += interval.offset
+				break
+			}
+		}
+	}
+	return current
+}
+
+func referenceLowestLocation(seeds []int64, maps [][]*OffsetInterval) int64 {
+	lowest := int64(-1)
+	for _, seed := range seeds {
+		if location := referenceLocation(seed, maps); lowest == -1 || location < lowest {
+			lowest = location
+		}
+	}
+	return lowest
+}
+
+// referenceLowestLocationOfRanges maps every single seed of every range, so it
+// is only usable on small inputs.
+func referenceLowestLocationOfRanges(seeds []int64, maps [][]*OffsetInterval) int64 {
+	lowest := int64(-1)
+	for i := 0; i < len(seeds)-1; i += 2 {
+		for seed := seeds[i]; seed < seeds[i]+seeds[i+1]; seed++ {
+			if location := referenceLocation(seed, maps); lowest == -1 || location < lowest {
+				lowest = location
+			}
+		}
+	}
+	return lowest
+}
+
+func runReference() {
+	seeds, maps, err := loadData("input.txt")
+	if err != nil {
+		fmt.Println("Error loading data:", err)
+		os.Exit(1)
+	}
+	mismatch := false
+	for _, check := range []struct {
+		name      string
+		fast      func(context.Context, []int64, [][]*OffsetInterval) (int64, error)
+		reference func([]int64, [][]*OffsetInterval) int64
+	}{
+		{"Part 1", lowestLocation, referenceLowestLocation},
+		{"Part 1 composed", lowestLocationComposed, referenceLowestLocation},
+		{"Part 2", lowestLocationOfRanges, referenceLowestLocationOfRanges},
+		{"Part 2 composed", lowestLocationOfRangesComposed, referenceLowestLocationOfRanges},
+		{"Part 2 inverse", lowestLocationOfRangesInverse, referenceLowestLocationOfRanges},
+	} {
+		fast, err := check.fast(context.Background(), append([]int64{}, seeds...), maps)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		reference := check.reference(seeds, maps)
+		fmt.Printf("%s: %d, reference: %d\n", check.name, fast, reference)
+		mismatch = mismatch || fast != reference
+	}
+	if mismatch {
+		fmt.Println("Error: fast solution differs from reference")
+		os.Exit(1)
+	}
+}
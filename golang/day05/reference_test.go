@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const example = `seeds: 79 14 55 13
+
+seed-to-soil map:
+50 98 2
+52 50 48
+
+soil-to-fertilizer map:
+0 15 37
+37 52 2
+39 0 15
+
+fertilizer-to-water map:
+49 53 8
+0 11 42
+42 0 7
+57 7 4
+
+water-to-light map:
+88 18 7
+18 25 70
+
+light-to-temperature map:
+45 77 23
+81 45 19
+68 64 13
+
+temperature-to-humidity map:
+0 69 1
+1 0 69
+
+humidity-to-location map:
+60 56 37
+56 93 4
+`
+
+var referenceTests = []struct {
+	name  string
+	input string
+}{
+	{"example", example},
+	{"identity", `seeds: 3 4 20 2
+
+seed-to-location map:
+`},
+	{"gap in the middle", `seeds: 0 30
+
+seed-to-soil map:
+100 10 5
+
+soil-to-location map:
+0 100 3
+`},
+	{"range across pieces", `seeds: 5 40
+
+seed-to-soil map:
+60 10 10
+0 20 10
+30 30 15
+
+soil-to-location map:
+50 0 5
+0 55 20
+`},
+	{"unsorted lines", `seeds: 7 3 40 9 90 5
+
+seed-to-soil map:
+20 90 10
+0 40 5
+10 0 8
+
+soil-to-fertilizer map:
+5 10 2
+0 20 5
+
+fertilizer-to-location map:
+70 0 3
+`},
+	{"maps to the same location", `seeds: 0 10
+
+seed-to-soil map:
+3 0 5
+3 5 5
+
+soil-to-location map:
+`},
+}
+
+func loadExample(t *testing.T, input string) ([]int64, [][]*OffsetInterval) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seeds, maps, err := loadData(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seeds, maps
+}
+
+func TestLowestLocation(t *testing.T) {
+	for _, test := range referenceTests {
+		t.Run(test.name, func(t *testing.T) {
+			seeds, maps := loadExample(t, test.input)
+			want := referenceLowestLocation(seeds, maps)
+			for name, fast := range map[string]func(context.Context, []int64, [][]*OffsetInterval) (int64, error){
+				"layered":  lowestLocation,
+				"composed": lowestLocationComposed,
+			} {
+				got, err := fast(context.Background(), slices.Clone(seeds), maps)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if got != want {
+					t.Errorf("%s: got %d, reference %d", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLowestLocationOfRanges(t *testing.T) {
+	for _, test := range referenceTests {
+		t.Run(test.name, func(t *testing.T) {
+			seeds, maps := loadExample(t, test.input)
+			want := referenceLowestLocationOfRanges(seeds, maps)
+			for name, fast := range map[string]func(context.Context, []int64, [][]*OffsetInterval) (int64, error){
+				"layered":  lowestLocationOfRanges,
+				"composed": lowestLocationOfRangesComposed,
+				"inverse":  lowestLocationOfRangesInverse,
+			} {
+				got, err := fast(context.Background(), slices.Clone(seeds), maps)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if got != want {
+					t.Errorf("%s: got %d, reference %d", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleAnswers(t *testing.T) {
+	seeds, maps := loadExample(t, example)
+	if got := referenceLowestLocation(seeds, maps); got != 35 {
+		t.Errorf("part 1 reference: got %d, want 35", got)
+	}
+	if got := referenceLowestLocationOfRanges(seeds, maps); got != 46 {
+		t.Errorf("part 2 reference: got %d, want 46", got)
+	}
+}
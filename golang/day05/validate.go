@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var validate = flag.Bool("validate", false, "check the almanac and print every problem instead of solving")
+
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = problem.String()
+	}
+	return "invalid almanac:\n" + strings.Join(lines, "\n")
+}
+
+// lastOf returns start+length-1 and false if that doesn't fit into an int64.
+func lastOf(start, length int64) (int64, bool) {
+	if start > math.MaxInt64-(length-1) {
+		return 0, false
+	}
+	return start + length - 1, true
+}
+
+// Validate checks that no seed is negative and that every map line has three
+// numbers, none of them negative, a positive length, ranges that fit into an
+// int64 and source ranges that don't overlap within a map.
+func (a *Almanac) Validate() []Problem {
+	problems := make([]Problem, 0)
+	for _, seed := range a.Seeds {
+		if seed < 0 {
+			problems = append(problems, Problem{a.SeedsLine, fmt.Sprintf("negative seed %d", seed)})
+		}
+	}
+	type source struct {
+		line       int
+		start, end int64
+	}
+	for _, mapping := range a.Mappings {
+		sources := make([]source, 0)
+		for _, entry := range mapping.entries {
+			numbers := entry.numbers
+			if len(numbers) != 3 {
+				problems = append(problems, Problem{entry.line, fmt.Sprintf("expected 3 numbers in the %s-to-%s map, got %d", mapping.From, mapping.To, len(numbers))})
+				continue
+			}
+			if numbers[0] < 0 || numbers[1] < 0 {
+				problems = append(problems, Problem{entry.line, "negative range start"})
+				continue
+			}
+			if numbers[2] <= 0 {
+				problems = append(problems, Problem{entry.line, fmt.Sprintf("range length %d is not positive", numbers[2])})
+				continue
+			}
+			end, ok := lastOf(numbers[1], numbers[2])
+			if !ok {
+				problems = append(problems, Problem{entry.line, fmt.Sprintf("source range %d+%d overflows int64", numbers[1], numbers[2])})
+				continue
+			}
+			if _, ok := lastOf(numbers[0], numbers[2]); !ok {
+				problems = append(problems, Problem{entry.line, fmt.Sprintf("destination range %d+%d overflows int64", numbers[0], numbers[2])})
+				continue
+			}
+			sources = append(sources, source{entry.line, numbers[1], end})
+		}
+		sort.Slice(sources, func(i, j int) bool {
+			return sources[i].start < sources[j].start
+		})
+		if len(sources) == 0 {
+			continue
+		}
+		// a range overlaps if it starts before the one reaching furthest so far
+		// has ended
+		furthest := sources[0]
+		for _, s := range sources[1:] {
+			if s.start <= furthest.end {
+				problems = append(problems, Problem{max(s.line, furthest.line), fmt.Sprintf("source range overlaps the one on line %d in the %s-to-%s map", min(s.line, furthest.line), mapping.From, mapping.To)})
+			}
+			if s.end > furthest.end {
+				furthest = s
+			}
+		}
+	}
+	sortProblems(problems)
+	return problems
+}
+
+func sortProblems(problems []Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+}
+
+// printValidation prints every problem of input.txt, including those of the
+// seed pairs, and fails if there are any.
+func printValidation() error {
+	almanac, err := readAlmanac("input.txt")
+	if err != nil {
+		return err
+	}
+	problems := append(almanac.Validate(), almanac.SeedPairProblems()...)
+	sortProblems(problems)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) == 1 {
+		return errors.New("1 problem found")
+	}
+	if len(problems) > 1 {
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+	almanac.build()
+	if _, err := almanac.Chain(*from, *to); err != nil {
+		return err
+	}
+	fmt.Println("almanac is valid")
+	return nil
+}
+
+// SeedPairProblems reports seeds that can't be read as start and length
+// pairs for part 2.
+func (a *Almanac) SeedPairProblems() []Problem {
+	problems := make([]Problem, 0)
+	if len(a.Seeds)%2 != 0 {
+		problems = append(problems, Problem{a.SeedsLine, fmt.Sprintf("odd number of seeds (%d), part 2 reads them as start and length pairs", len(a.Seeds))})
+	}
+	for i := 0; i+1 < len(a.Seeds); i += 2 {
+		if a.Seeds[i+1] <= 0 {
+			problems = append(problems, Problem{a.SeedsLine, fmt.Sprintf("seed range %d has length %d, which is not positive", a.Seeds[i], a.Seeds[i+1])})
+			continue
+		}
+		if _, ok := lastOf(a.Seeds[i], a.Seeds[i+1]); !ok {
+			problems = append(problems, Problem{a.SeedsLine, fmt.Sprintf("seed range %d+%d overflows int64", a.Seeds[i], a.Seeds[i+1])})
+		}
+	}
+	return problems
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"math"
 	"os"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 func loadDataPart1(filename string) ([]int, []int, error) {
@@ -64,7 +69,12 @@ func parseNumbersString(listString string) ([]int, error) {
 
 func calculateRange(time float64, distance float64) int {
 	p_2 := time / 2.0
-	d := math.Sqrt(math.Pow(time/2.0, 2.0) - distance)
+	discriminant := math.Pow(time/2.0, 2.0) - distance
+	// at best the record is tied, which doesn't win
+	if discriminant <= 0 {
+		return 0
+	}
+	d := math.Sqrt(discriminant)
 	zero_1 := p_2 - d
 	zero_2 := p_2 + d
 	high := math.Floor(zero_2)
@@ -78,14 +88,16 @@ func calculateRange(time float64, distance float64) int {
 	return int(high) - int(low) + 1
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	distances, times, err := loadDataPart1("input.txt")
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return err
 	}
 	ranges := make([]int, 0)
 	for i := 0; i < len(times); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		ranges = append(ranges, calculateRange(float64(times[i]), float64(distances[i])))
 	}
 	product := 1
@@ -93,19 +105,31 @@ func solutionPart1() {
 		product *= r
 	}
 	fmt.Println("Product:", product)
+	return nil
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	time, distance, err := loadDataPart2("input.txt")
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return err
 	}
 	pressTime := calculateRange(float64(time), float64(distance))
 	fmt.Println("Range:", pressTime)
+	return nil
 }
 
+var reference = flag.Bool("reference", false, "cross-check against the brute-force reference solver")
+
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if *reference {
+		runReference()
+		return
+	}
+	if err := run.Part(6, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(6, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// referenceRange counts the winning hold times by trying every single one.
+func referenceRange(time int, distance int) int {
+	count := 0
+	for hold := 0; hold <= time; hold++ {
+		if hold*(time-hold) > distance {
+			count++
+		}
+	}
+	return count
+}
+
+func runReference() {
+	distances, times, err := loadDataPart1("input.txt")
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	time, distance, err := loadDataPart2("input.txt")
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	times = append(times, time)
+	distances = append(distances, distance)
+	mismatch := false
+	for i := 0; i < len(times); i++ {
+		fast := calculateRange(float64(times[i]), float64(distances[i]))
+		reference := referenceRange(times[i], distances[i])
+		fmt.Printf("Time %d, Distance %d: %d, reference: %d\n", times[i], distances[i], fast, reference)
+		mismatch = mismatch || fast != reference
+	}
+	if mismatch {
+		fmt.Println("Error: fast solution differs from reference")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCalculateRange(t *testing.T) {
+	tests := []struct {
+		time, distance int
+	}{
+		// example
+		{7, 9},
+		{15, 40},
+		{30, 200},
+		{71530, 940200},
+		// the zeros are whole numbers
+		{10, 24},
+		{10, 21},
+		// only a tie is possible
+		{10, 25},
+		// the record can't be reached
+		{5, 10},
+		{0, 0},
+		{1, 0},
+		{2, 0},
+	}
+	for _, test := range tests {
+		got := calculateRange(float64(test.time), float64(test.distance))
+		want := referenceRange(test.time, test.distance)
+		if got != want {
+			t.Errorf("time %d, distance %d: got %d, reference %d", test.time, test.distance, got, want)
+		}
+	}
+}
+
+func TestExample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("Time:      7  15   30\nDistance:  9  40  200\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	distances, times, err := loadDataPart1(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	product := 1
+	for i := range times {
+		product *= calculateRange(float64(times[i]), float64(distances[i]))
+	}
+	if product != 288 {
+		t.Errorf("part 1: got %d, want 288", product)
+	}
+	time, distance, err := loadDataPart2(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := calculateRange(float64(time), float64(distance)); got != 71503 {
+		t.Errorf("part 2: got %d, want 71503", got)
+	}
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 type TypeOfHand int
@@ -139,14 +144,16 @@ func cardValue(card rune, jValue int) int {
 	return int(card - '0')
 }
 
-func solution(filename string, parseFunc func(string) TypeOfHand, jValue int) {
+func solution(ctx context.Context, filename string, parseFunc func(string) TypeOfHand, jValue int) error {
 	handStrs, bids, err := loadData(filename)
 	if err != nil {
-		fmt.Println("Error loading data:", err)
-		return
+		return err
 	}
 	hands := make([]Hand, len(bids))
 	for index, handStr := range handStrs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		hands[index] = Hand{[]rune(handStr), parseFunc(handStr), bids[index]}
 	}
 	slices.SortFunc(hands, createCmpFunction(jValue))
@@ -155,17 +162,23 @@ func solution(filename string, parseFunc func(string) TypeOfHand, jValue int) {
 		winnings += (index + 1) * hand.bid
 	}
 	fmt.Printf("Total: %+v\n", winnings)
+	return nil
 }
 
-func solutionPart1() {
-	solution("input.txt", parseHandPart1, 11)
+func solutionPart1(ctx context.Context) error {
+	return solution(ctx, "input.txt", parseHandPart1, 11)
 }
 
-func solutionPart2() {
-	solution("input.txt", parseHandPart2, 1)
+func solutionPart2(ctx context.Context) error {
+	return solution(ctx, "input.txt", parseHandPart2, 1)
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(7, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(7, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
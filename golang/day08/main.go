@@ -2,9 +2,15 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
+	"log"
 	"os"
 	"regexp"
 	"strings"
+
+	"aoc/run"
+	"aoc/shrink"
 )
 
 type Direction int
@@ -68,46 +74,70 @@ func loadData(filename string) ([]Direction, map[string]map[Direction]string, er
 	return directions, network, nil
 }
 
-func followPath(start string, directions []Direction, network map[string]map[Direction]string, checkFn func(string) bool) int {
+func followPath(ctx context.Context, start string, directions []Direction, network map[string]map[Direction]string, checkFn func(string) bool) (int, error) {
 	currentNode := start
 	steps := 0
 	currentIndex := 0
 	for checkFn(currentNode) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		currentNode = network[currentNode][directions[currentIndex]]
 		steps++
 		currentIndex = (currentIndex + 1) % len(directions)
 	}
-	return steps
+	return steps, nil
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	directions, network, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
-	steps := followPath("AAA", directions, network, func(node string) bool { return node != "ZZZ" })
+	steps, err := followPath(ctx, "AAA", directions, network, func(node string) bool { return node != "ZZZ" })
+	if err != nil {
+		return err
+	}
 	println(steps)
+	return nil
 }
 
-func solutionPart2() {
-	directions, network, err := loadData("input.txt")
-	if err != nil {
-		panic(err)
-	}
-	cycles := make([]int, 0)
+func lcmSteps(ctx context.Context, directions []Direction, network map[string]map[Direction]string) (int, error) {
+	currentLcm := 1
 	for key := range network {
 		if strings.HasSuffix(key, "A") {
-			cycles = append(cycles, followPath(key, directions, network, func(node string) bool { return !strings.HasSuffix(node, "Z") }))
+			cycle, err := followPath(ctx, key, directions, network, func(node string) bool { return !strings.HasSuffix(node, "Z") })
+			if err != nil {
+				return 0, err
+			}
+			currentLcm = lcm(currentLcm, cycle)
 		}
 	}
-	currentLcm := 1
-	for _, cycle := range cycles {
-		currentLcm = lcm(currentLcm, cycle)
+	return currentLcm, nil
+}
+
+func solutionPart2(ctx context.Context) error {
+	directions, network, err := loadData("input.txt")
+	if err != nil {
+		panic(err)
+	}
+	steps, err := lcmSteps(ctx, directions, network)
+	if err != nil {
+		return err
 	}
-	println(currentLcm)
+	println(steps)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if shrink.Main(shrinker) {
+		return
+	}
+	if err := run.Part(8, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(8, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
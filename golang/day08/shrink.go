@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+
+	"aoc/shrink"
+)
+
+const maxLockstepSteps = 10000000
+
+// lockstepSteps walks all ghosts at once until they are all on a node ending
+// in Z. It gives up after maxLockstepSteps.
+func lockstepSteps(directions []Direction, network map[string]map[Direction]string) (int, bool) {
+	nodes := make([]string, 0)
+	for key := range network {
+		if strings.HasSuffix(key, "A") {
+			nodes = append(nodes, key)
+		}
+	}
+	for steps := 0; steps < maxLockstepSteps; steps++ {
+		done := true
+		for _, node := range nodes {
+			if !strings.HasSuffix(node, "Z") {
+				done = false
+				break
+			}
+		}
+		if done {
+			return steps, true
+		}
+		for i, node := range nodes {
+			nodes[i] = network[node][directions[steps%len(directions)]]
+		}
+	}
+	return 0, false
+}
+
+var nodeRegex = regexp.MustCompile(`\w{3}`)
+
+var shrinker = shrink.Day{
+	Predicates: map[string]func() bool{
+		// lcm: part 2 differs from walking all ghosts in lockstep.
+		"lcm": func() bool {
+			directions, network, err := loadData("input.txt")
+			if err != nil {
+				log.Fatal(err)
+			}
+			expected, ok := lockstepSteps(directions, network)
+			if !ok {
+				return false
+			}
+			steps, err := lcmSteps(context.Background(), directions, network)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return steps != expected
+		},
+	},
+	// Reduce removes nodes first and then directions.
+	Reduce: func(input string, fails func(string) bool) string {
+		lines := strings.Split(input, "\n")
+		directions := []rune(lines[0])
+		nodes := shrink.Slice(lines[2:], func(nodes []string) bool {
+			return fails(string(directions) + "\n\n" + strings.Join(nodes, "\n"))
+		})
+		directions = shrink.Slice(directions, func(directions []rune) bool {
+			return fails(string(directions) + "\n\n" + strings.Join(nodes, "\n"))
+		})
+		return string(directions) + "\n\n" + strings.Join(nodes, "\n")
+	},
+	// Anonymize renames the nodes but keeps AAA, ZZZ and whether a node ends
+	// in A or Z.
+	Anonymize: func(input string) string {
+		renamer := shrink.NewRenamer(func(id string, n int) string {
+			suffix := "N"
+			if strings.HasSuffix(id, "A") || strings.HasSuffix(id, "Z") {
+				suffix = id[len(id)-1:]
+			}
+			return shrink.Letters(n, 2, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ") + suffix
+		}, "AAA", "ZZZ")
+		directions, nodes, _ := strings.Cut(input, "\n")
+		return directions + "\n" + renamer.ReplaceAll(nodeRegex, nodes)
+	},
+}
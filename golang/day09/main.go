@@ -2,11 +2,15 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"log"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 func loadData(filename string) ([][]int, error) {
@@ -83,28 +87,37 @@ func predictBeginning(lines [][]int) int {
 	return acc
 }
 
-func solution(predictFn func([][]int) int) {
+func solution(ctx context.Context, predictFn func([][]int) int) error {
 	data, err := loadData("input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 	sum := 0
 	for _, line := range data {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		processedLines := processLine(line)
 		sum += predictFn(processedLines)
 	}
 	println(sum)
+	return nil
 }
 
-func solutionPart1() {
-	solution(predictEnd)
+func solutionPart1(ctx context.Context) error {
+	return solution(ctx, predictEnd)
 }
 
-func solutionPart2() {
-	solution(predictBeginning)
+func solutionPart2(ctx context.Context) error {
+	return solution(ctx, predictBeginning)
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(9, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(9, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
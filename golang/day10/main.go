@@ -2,9 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"image/color"
+	"log"
 	"os"
 	"slices"
+
+	"aoc/animation"
+	"aoc/render"
+	"aoc/run"
 )
 
 type Direction int
@@ -181,7 +189,23 @@ func replaceStart(tileMap [][]Tile, start Point) {
 	tileMap[start.y][start.x] = newTile(symbol)
 }
 
-func followPath(tileMap [][]Tile, start Point) []Point {
+var recorder *animation.Recorder
+
+func picture(tileMap [][]Tile, path []Point) *render.Picture {
+	rows := make(render.Runes, len(tileMap))
+	for y, row := range tileMap {
+		for _, tile := range row {
+			rows[y] = append(rows[y], tile.symbol)
+		}
+	}
+	pathPoints := make([]render.Point, len(path))
+	for i, p := range path {
+		pathPoints[i] = render.Point{X: p.x, Y: p.y}
+	}
+	return render.New(rows, render.Highlight{Points: pathPoints, Color: color.RGBA{60, 160, 255, 255}})
+}
+
+func followPath(ctx context.Context, tileMap [][]Tile, start Point, rec *animation.Recorder) ([]Point, error) {
 	currentTile := start
 	startTile := tileMap[start.y][start.x].connections
 	pathTiles := make([]Point, 0)
@@ -193,21 +217,28 @@ func followPath(tileMap [][]Tile, start Point) []Point {
 		}
 	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		currentTile = currentTile.getNeighbourInDirection(currentDirection)
 		currentDirection = tileMap[currentTile.y][currentTile.x].path[currentDirection.opposite()]
 		pathTiles = append(pathTiles, currentTile)
+		rec.Frame(func() *render.Picture { return picture(tileMap, pathTiles) })
 		if currentTile == start {
 			break
 		}
 	}
-	return pathTiles
+	return pathTiles, nil
 }
 
-func scanRow(tileMap [][]Tile, path []Point) int {
+func scanRow(ctx context.Context, tileMap [][]Tile, path []Point) (int, error) {
 	interiorSum := 0
 	height := len(tileMap)
 	width := len(tileMap[0])
 	for y := 1; y < height-1; y++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		isInside := false
 		x := 1
 		for x < width-1 {
@@ -239,10 +270,10 @@ func scanRow(tileMap [][]Tile, path []Point) int {
 			x++
 		}
 	}
-	return interiorSum
+	return interiorSum, nil
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	tileMap, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -252,11 +283,15 @@ func solutionPart1() {
 		panic(err)
 	}
 	replaceStart(tileMap, start)
-	pathPoints := followPath(tileMap, start)
+	pathPoints, err := followPath(ctx, tileMap, start, recorder)
+	if err != nil {
+		return err
+	}
 	fmt.Println(len(pathPoints) / 2)
+	return nil
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	tileMap, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -266,12 +301,28 @@ func solutionPart2() {
 		panic(err)
 	}
 	replaceStart(tileMap, start)
-	pathPoints := followPath(tileMap, start)
-	interiorSum := scanRow(tileMap, pathPoints)
+	pathPoints, err := followPath(ctx, tileMap, start, nil)
+	if err != nil {
+		return err
+	}
+	interiorSum, err := scanRow(ctx, tileMap, pathPoints)
+	if err != nil {
+		return err
+	}
 	println(interiorSum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	recorder = animation.FromFlags()
+	if err := run.Part(10, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(10, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -2,8 +2,13 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
+	"log"
 	"os"
 	"sort"
+
+	"aoc/run"
 )
 
 type Point = map[string]int
@@ -58,35 +63,47 @@ func abs(num int) int {
 	return num
 }
 
-func sumDistances(galaxies []Point) int {
+func sumDistances(ctx context.Context, galaxies []Point) (int, error) {
 	sum := 0
 	for i := 0; i < len(galaxies); i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		for j := i + 1; j < len(galaxies); j++ {
 			sum += abs(galaxies[i]["x"]-galaxies[j]["x"]) + abs(galaxies[i]["y"]-galaxies[j]["y"])
 		}
 	}
-	return sum
+	return sum, nil
 }
 
-func solution(filename string, spreadFactor int) {
+func solution(ctx context.Context, filename string, spreadFactor int) error {
 	galaxies, err := loadData(filename)
 	if err != nil {
 		panic(err)
 	}
 	spreadGalaxies(galaxies, spreadFactor)
-	totalDistance := sumDistances(galaxies)
+	totalDistance, err := sumDistances(ctx, galaxies)
+	if err != nil {
+		return err
+	}
 	println(totalDistance)
+	return nil
 }
 
-func solutionPart1() {
-	solution("input.txt", 2)
+func solutionPart1(ctx context.Context) error {
+	return solution(ctx, "input.txt", 2)
 }
 
-func solutionPart2() {
-	solution("input.txt", 1000000)
+func solutionPart2(ctx context.Context) error {
+	return solution(ctx, "input.txt", 1000000)
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(11, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(11, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -2,10 +2,15 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 type Condition = int
@@ -128,36 +133,56 @@ func multiplyRow(row *Row, factor int) {
 	}
 }
 
-func solution(factor int) {
+func countArrangements(row Row) int {
+	cache := make([][]int, len(row.Conditions)+2)
+	for i := 0; i < len(row.Conditions)+2; i++ {
+		cacheRow := make([]int, len(row.Blocks)+2)
+		for j := 0; j < len(row.Blocks)+2; j++ {
+			cacheRow[j] = -1
+		}
+		cache[i] = cacheRow
+	}
+	p := Process{row, 0, cache}
+	return p.processRow(0, 0)
+}
+
+func solution(ctx context.Context, factor int) error {
 	rows, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
 	totalSum := 0
 	for _, row := range rows {
-		multiplyRow(&row, factor)
-		cache := make([][]int, len(row.Conditions)+2)
-		for i := 0; i < len(row.Conditions)+2; i++ {
-			cacheRow := make([]int, len(row.Blocks)+2)
-			for j := 0; j < len(row.Blocks)+2; j++ {
-				cacheRow[j] = -1
-			}
-			cache[i] = cacheRow
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-		p := Process{row, 0, cache}
-		totalSum += p.processRow(0, 0)
+		multiplyRow(&row, factor)
+		totalSum += countArrangements(row)
 	}
 	fmt.Printf("Sum: %d\n", totalSum)
+	return nil
 }
 
-func solutionPart1() {
-	solution(1)
+func solutionPart1(ctx context.Context) error {
+	return solution(ctx, 1)
 }
 
-func solutionPart2() {
-	solution(5)
+func solutionPart2(ctx context.Context) error {
+	return solution(ctx, 5)
 }
+
+var reference = flag.Bool("reference", false, "cross-check against the brute-force reference solver")
+
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if *reference {
+		runReference()
+		return
+	}
+	if err := run.Part(12, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(12, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
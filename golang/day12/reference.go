@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// maxReferenceUnknowns bounds the rows the reference solver is tried on, as
+// it enumerates all 2^n assignments of the unknown conditions.
+const maxReferenceUnknowns = 20
+
+func blocksOf(conditions []Condition) []int {
+	blocks := make([]int, 0)
+	current := 0
+	for _, condition := range conditions {
+		if condition == Damaged {
+			current++
+		} else if current > 0 {
+			blocks = append(blocks, current)
+			current = 0
+		}
+	}
+	if current > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// referenceArrangements tries every assignment of the unknown conditions and
+// counts those producing the expected blocks.
+func referenceArrangements(row Row) int {
+	unknowns := make([]int, 0)
+	for i, condition := range row.Conditions {
+		if condition == Unknown {
+			unknowns = append(unknowns, i)
+		}
+	}
+	conditions := slices.Clone(row.Conditions)
+	count := 0
+	for mask := 0; mask < 1<<len(unknowns); mask++ {
+		for bit, index := range unknowns {
+			if mask&(1<<bit) != 0 {
+				conditions[index] = Damaged
+			} else {
+				conditions[index] = Operational
+			}
+		}
+		if slices.Equal(blocksOf(conditions), row.Blocks) {
+			count++
+		}
+	}
+	return count
+}
+
+func countUnknowns(row Row) int {
+	count := 0
+	for _, condition := range row.Conditions {
+		if condition == Unknown {
+			count++
+		}
+	}
+	return count
+}
+
+func runReference() {
+	rows, err := loadData("input.txt")
+	if err != nil {
+		panic(err)
+	}
+	checked, skipped, mismatches := 0, 0, 0
+	for _, factor := range []int{1, 2} {
+		for i, row := range rows {
+			row = Row{slices.Clone(row.Conditions), slices.Clone(row.Blocks)}
+			multiplyRow(&row, factor)
+			if countUnknowns(row) > maxReferenceUnknowns {
+				skipped++
+				continue
+			}
+			checked++
+			fast := countArrangements(row)
+			reference := referenceArrangements(row)
+			if fast != reference {
+				fmt.Printf("Row %d (factor %d): %d, reference: %d\n", i+1, factor, fast, reference)
+				mismatches++
+			}
+		}
+	}
+	fmt.Printf("Checked: %d, Skipped: %d, Mismatches: %d\n", checked, skipped, mismatches)
+	if mismatches > 0 {
+		os.Exit(1)
+	}
+}
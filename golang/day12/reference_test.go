@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const example = `???.### 1,1,3
+.??..??...?##. 1,1,3
+?#?#?#?#?#?#?#? 1,3,1,6
+????.#...#... 4,1,1
+????.######..#####. 1,6,5
+?###???????? 3,2,1
+`
+
+// edgeCases have no arrangement, a single one or only unknown springs.
+const edgeCases = `### 3
+### 2
+#.# 1,1
+?#? 3
+??? 4
+????? 1
+.???? 2
+#?#?# 1,1,1
+??#??.?#? 2,2
+`
+
+func loadExample(t *testing.T, input string) []Row {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := loadData(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestCountArrangements(t *testing.T) {
+	for _, input := range []string{example, edgeCases} {
+		for i, row := range loadExample(t, input) {
+			for _, factor := range []int{1, 2, 3} {
+				row := Row{slices.Clone(row.Conditions), slices.Clone(row.Blocks)}
+				multiplyRow(&row, factor)
+				if countUnknowns(row) > maxReferenceUnknowns {
+					continue
+				}
+				got, want := countArrangements(row), referenceArrangements(row)
+				if got != want {
+					t.Errorf("row %d, factor %d: got %d, reference %d", i+1, factor, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestExampleAnswers(t *testing.T) {
+	for _, test := range []struct {
+		factor, want int
+	}{
+		{1, 21},
+		{5, 525152},
+	} {
+		sum := 0
+		for _, row := range loadExample(t, example) {
+			multiplyRow(&row, test.factor)
+			sum += countArrangements(row)
+		}
+		if sum != test.want {
+			t.Errorf("factor %d: got %d, want %d", test.factor, sum, test.want)
+		}
+	}
+}
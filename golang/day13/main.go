@@ -2,9 +2,14 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"slices"
+
+	"aoc/run"
 )
 
 func loadData(filename string) ([][]string, error) {
@@ -94,7 +99,7 @@ func transposeGrid(grid []string) []string {
 	return transposedGrid
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	grids, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -102,6 +107,9 @@ func solutionPart1() {
 	horizontalSum := 0
 	verticalSum := 0
 	for _, grid := range grids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		horizontalStartingPoints := scanForHorizontalReflectionLine(grid, isStrictEqual)
 		if len(horizontalStartingPoints) == 1 {
 			horizontalSum += horizontalStartingPoints[0] + 1
@@ -117,6 +125,7 @@ func solutionPart1() {
 	}
 	totalSum := verticalSum + horizontalSum*100
 	fmt.Println(totalSum)
+	return nil
 }
 
 func getCorrectedStartingPoint(grid []string) []int {
@@ -130,7 +139,7 @@ func getCorrectedStartingPoint(grid []string) []int {
 	return diff
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	grids, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -138,6 +147,9 @@ func solutionPart2() {
 	horizontalSum := 0
 	verticalSum := 0
 	for _, grid := range grids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		horizontalStartingPoints := getCorrectedStartingPoint(grid)
 		if len(horizontalStartingPoints) == 1 {
 			horizontalSum += horizontalStartingPoints[0] + 1
@@ -153,9 +165,15 @@ func solutionPart2() {
 	}
 	totalSum := verticalSum + horizontalSum*100
 	fmt.Println(totalSum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(13, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(13, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
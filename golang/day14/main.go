@@ -2,10 +2,20 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"crypto/sha1"
+	"flag"
 	"fmt"
+	"image/color"
+	"log"
 	"math"
 	"os"
+	"slices"
+
+	"aoc/animation"
+	"aoc/cycle"
+	"aoc/render"
+	"aoc/run"
 )
 
 func loadData(filename string) ([]byte, error) {
@@ -64,7 +74,7 @@ func calculateLoad(grid []byte, dim int) int {
 	return load
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	grid, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -73,6 +83,7 @@ func solutionPart1() {
 	tiltNorth(grid, dim)
 	load := calculateLoad(grid, dim)
 	fmt.Printf("Load: %d\n", load)
+	return nil
 }
 
 func rotateGrid(grid []byte, dim int) {
@@ -90,46 +101,62 @@ func rotateGrid(grid []byte, dim int) {
 	}
 }
 
-func cycle(grid []byte, dim int) {
+func spinCycle(grid []byte, dim int) {
 	for i := 0; i < 4; i++ {
 		tiltNorth(grid, dim)
 		rotateGrid(grid, dim)
 	}
 }
 
-func findCycle(grid []byte, dim int) (int, int) {
-	cache := make(map[[20]byte]int)
-	cache[sha1.Sum(grid)] = 0
-	index := 0
-	for {
-		cycle(grid, dim)
-		if lastIndex, ok := cache[sha1.Sum(grid)]; ok {
-			return index - lastIndex, index + 1
-		} else {
-			cache[sha1.Sum(grid)] = index
-		}
-		index += 1
+var recorder *animation.Recorder
+
+var rockPalette = map[rune]color.Color{
+	'#': color.RGBA{200, 200, 200, 255},
+	'.': color.RGBA{20, 20, 20, 255},
+	'O': color.RGBA{230, 140, 30, 255},
+}
+
+func picture(grid []byte, dim int) *render.Picture {
+	rows := make(render.Runes, dim)
+	for row := range rows {
+		rows[row] = []rune(string(grid[toFlatIndex(row, 0, dim):toFlatIndex(row+1, 0, dim)]))
 	}
+	p := render.New(rows)
+	p.Palette = rockPalette
+	return p
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	grid, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
 	dim := int(math.Sqrt(float64(len(grid))))
-	remainingCycles := 1000000000
-	cycleLength, currentCycle := findCycle(grid, dim)
-	remainingCycles -= currentCycle
-	remainingCycles -= (remainingCycles / cycleLength) * cycleLength
-	for i := 0; i < remainingCycles; i++ {
-		cycle(grid, dim)
+	step := func(grid []byte) []byte {
+		next := slices.Clone(grid)
+		spinCycle(next, dim)
+		recorder.Frame(func() *render.Picture { return picture(next, dim) })
+		return next
+	}
+	grid, err = cycle.NthContext(ctx, grid, step, sha1.Sum, 1000000000)
+	if err != nil {
+		return err
 	}
 	load := calculateLoad(grid, dim)
 	fmt.Printf("Load: %d\n", load)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	recorder = animation.FromFlags()
+	if err := run.Part(14, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(14, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
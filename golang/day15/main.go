@@ -2,11 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"aoc/run"
 )
 
 func loadData(filename string) ([]string, error) {
@@ -39,16 +44,20 @@ func hash(input string) int {
 	return int(hashSum)
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	ops, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
 	}
 	totalSum := 0
 	for _, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		totalSum += hash(op)
 	}
 	fmt.Println(totalSum)
+	return nil
 }
 
 type Box struct {
@@ -83,7 +92,7 @@ func (b *Box) remove(label string) {
 	}
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	ops, err := loadData("input.txt")
 	if err != nil {
 		panic(err)
@@ -97,6 +106,9 @@ func solutionPart2() {
 		boxes[i] = newBox()
 	}
 	for _, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		match := opMatcher.FindStringSubmatch(op)
 		label := match[1]
 		opType := match[2]
@@ -125,9 +137,15 @@ func solutionPart2() {
 		}
 	}
 	fmt.Println(totalSum)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	if err := run.Part(15, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(15, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
 }
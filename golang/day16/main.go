@@ -2,8 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"image/color"
+	"log"
 	"os"
+
+	"aoc/animation"
+	"aoc/render"
+	"aoc/run"
 )
 
 type Direction = int
@@ -148,7 +156,24 @@ func processLeftRight(status Status) []Status {
 	return []Status{status}
 }
 
-func calculateEnergizedTiles(grid [][]rune, start Status) int {
+var recorder *animation.Recorder
+
+func picture(grid [][]rune, energized map[Point]struct{}, beams []Status) *render.Picture {
+	energizedPoints := make([]render.Point, 0, len(energized))
+	for p := range energized {
+		energizedPoints = append(energizedPoints, render.Point{X: p.x, Y: p.y})
+	}
+	beamPoints := make([]render.Point, 0, len(beams))
+	for _, beam := range beams {
+		beamPoints = append(beamPoints, render.Point{X: beam.x, Y: beam.y})
+	}
+	return render.New(render.Runes(grid),
+		render.Highlight{Points: energizedPoints, Color: color.RGBA{120, 90, 20, 255}},
+		render.Highlight{Points: beamPoints, Color: color.RGBA{255, 230, 80, 255}},
+	)
+}
+
+func calculateEnergizedTiles(grid [][]rune, start Status, rec *animation.Recorder) int {
 	visited := make(map[Status]struct{})
 	count := make(map[Point]struct{})
 	queue := make([]Status, 0, 5)
@@ -161,6 +186,7 @@ func calculateEnergizedTiles(grid [][]rune, start Status) int {
 		}
 		visited[current] = struct{}{}
 		count[current.Point] = struct{}{}
+		rec.Frame(func() *render.Picture { return picture(grid, count, append(queue, current)) })
 		current.move()
 		tile := grid[current.y][current.x]
 		switch tile {
@@ -186,17 +212,18 @@ func calculateEnergizedTiles(grid [][]rune, start Status) int {
 	return len(count) - 1
 }
 
-func solutionPart1() {
+func solutionPart1(ctx context.Context) error {
 	grid, err := loadData("input.txt")
 	grid = padGrid(grid)
 	if err != nil {
 		panic(err)
 	}
-	count := calculateEnergizedTiles(grid, Status{Point{x: 0, y: 1}, RIGHT})
+	count := calculateEnergizedTiles(grid, Status{Point{x: 0, y: 1}, RIGHT}, recorder)
 	fmt.Println("Count:", count)
+	return nil
 }
 
-func solutionPart2() {
+func solutionPart2(ctx context.Context) error {
 	grid, err := loadData("input.txt")
 	grid = padGrid(grid)
 	if err != nil {
@@ -204,19 +231,32 @@ func solutionPart2() {
 	}
 	energizedTiles := make([]int, 0)
 	for i := 1; i < len(grid)-1; i++ {
-		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{0, i}, RIGHT}))
-		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{len(grid) - 1, i}, LEFT}))
-		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{i, 0}, DOWN}))
-		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{i, len(grid) - 1}, UP}))
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{0, i}, RIGHT}, nil))
+		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{len(grid) - 1, i}, LEFT}, nil))
+		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{i, 0}, DOWN}, nil))
+		energizedTiles = append(energizedTiles, calculateEnergizedTiles(grid, Status{Point{i, len(grid) - 1}, UP}, nil))
 	}
 	maxTiles := 0
 	for _, el := range energizedTiles {
 		maxTiles = max(maxTiles, el)
 	}
 	fmt.Println("Count:", maxTiles)
+	return nil
 }
 
 func main() {
-	solutionPart1()
-	solutionPart2()
+	flag.Parse()
+	recorder = animation.FromFlags()
+	if err := run.Part(16, 1, solutionPart1); err != nil {
+		log.Fatal(err)
+	}
+	if err := run.Part(16, 2, solutionPart2); err != nil {
+		log.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
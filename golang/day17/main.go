@@ -2,11 +2,16 @@ package main
 
 import (
 	"bytes"
-	"fmt"
+	"context"
+	"flag"
+	"log"
 	"os"
 	"slices"
 	"strconv"
 	"time"
+
+	"aoc/logging"
+	"aoc/run"
 )
 
 func readData(filepath string) ([][]int, error) {
@@ -67,7 +72,7 @@ func getPoint(pos Vec, dir Vec, height, width int) (Vec, bool) {
 	return Vec{X: pos.X + dir.X, Y: pos.Y + dir.Y}, true
 }
 
-func solutionPart1(weights grid) int {
+func solutionPart1(ctx context.Context, weights grid) (int, error) {
 	end := []int{len(weights[0]) - 1, len(weights) - 1}
 	weightGrid := map[Status]int{
 		{Direction: RIGHT, Position: Vec{X: 1, Y: 0}, Count: 1}: weights.get(Vec{X: 1, Y: 0}),
@@ -75,6 +80,9 @@ func solutionPart1(weights grid) int {
 	}
 	queue := []Vec{{X: 1, Y: 0}, {X: 0, Y: 1}}
 	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		pos := queue[0]
 		queue = queue[1:]
 		for _, dir := range []Vec{UP, RIGHT, DOWN, LEFT} {
@@ -120,10 +128,10 @@ func solutionPart1(weights grid) int {
 		}
 	}
 
-	return slices.Min(endWeights)
+	return slices.Min(endWeights), nil
 }
 
-func solutionPart2(weights grid) int {
+func solutionPart2(ctx context.Context, weights grid) (int, error) {
 	end := []int{len(weights[0]) - 1, len(weights) - 1}
 	weightGrid := map[Status]int{
 		{Direction: RIGHT, Position: Vec{X: 1, Y: 0}, Count: 1}: weights.get(Vec{X: 1, Y: 0}),
@@ -131,6 +139,9 @@ func solutionPart2(weights grid) int {
 	}
 	queue := []Vec{{X: 1, Y: 0}, {X: 0, Y: 1}}
 	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		pos := queue[0]
 		queue = queue[1:]
 		for _, dir := range []Vec{UP, RIGHT, DOWN, LEFT} {
@@ -186,18 +197,32 @@ func solutionPart2(weights grid) int {
 		}
 	}
 
-	return slices.Min(endWeights)
+	return slices.Min(endWeights), nil
 }
 
 func main() {
+	flag.Parse()
+	logging.Setup("day17")
 	grid, readErr := readData("input.txt")
 	if readErr != nil {
 		panic(readErr)
 	}
 	startTime := time.Now()
-	println(solutionPart1(grid))
-	fmt.Printf("Part1: %s\n", time.Since(startTime))
+	part1, err := run.Answer(17, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, grid)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	println(part1)
+	logging.Debug("Part 1 done", "duration", time.Since(startTime))
 	startTime = time.Now()
-	println(solutionPart2(grid))
-	fmt.Printf("Part2: %s\n", time.Since(startTime))
+	part2, err := run.Answer(17, 2, func(ctx context.Context) (int, error) {
+		return solutionPart2(ctx, grid)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	println(part2)
+	logging.Debug("Part 2 done", "duration", time.Since(startTime))
 }
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+
+	"aoc/logging"
+	"aoc/render"
+	"aoc/run"
 )
 
 type Point struct {
@@ -72,18 +78,12 @@ func readDataPart2(filepath string) ([]Cmd, error) {
 }
 
 func printGrid(minY, maxY, minX, maxX int, border map[Point]bool) string {
-	sb := strings.Builder{}
-	for y := minY - 1; y <= maxY+1; y++ {
-		for x := minX - 1; x <= maxX+1; x++ {
-			if border[Point{x, y}] {
-				sb.WriteString("#")
-			} else {
-				sb.WriteString(".")
-			}
-		}
-		sb.WriteString("\n")
+	points := make([]render.Point, 0, len(border))
+	for point := range border {
+		points = append(points, render.Point{X: point.X - minX + 1, Y: point.Y - minY + 1})
 	}
-	return sb.String()
+	grid := render.Blank{Width: maxX - minX + 3, Height: maxY - minY + 3, Fill: '.'}
+	return render.New(grid, render.Path{Points: points, Rune: '#'}).Text()
 }
 
 func mod(a, b int) int {
@@ -97,13 +97,16 @@ func abs(a int) int {
 	return a
 }
 
-func solutionPart1(cmds []Cmd) int {
+func solutionPart1(ctx context.Context, cmds []Cmd) (int, error) {
 	// Start from (0, 0)
 	currentPoint := Point{0, 0}
 	corners := []Point{currentPoint}
 	borderLen := 0
 	// Loop through all commands
 	for _, cmd := range cmds {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		switch cmd.Direction {
 		case UP:
 			currentPoint.Y += cmd.Steps
@@ -121,40 +124,51 @@ func solutionPart1(cmds []Cmd) int {
 		corners = append(corners, currentPoint)
 	}
 
-	fmt.Println("Outer Corners: ", 4+(len(cmds)-4)/2)
-	fmt.Println("Inner Corners: ", (len(cmds)-4)/2)
+	logging.Debug("Corners", "outer", 4+(len(cmds)-4)/2, "inner", (len(cmds)-4)/2)
 	correctionCorners := ((4+(len(cmds)-4)/2)*3 + ((len(cmds) - 4) / 2)) / 4
-	fmt.Println("Correction Corners: ", correctionCorners)
+	logging.Debug("Correction", "corners", correctionCorners)
 	remainingCorrection := (borderLen - len(cmds)) / 2
-	fmt.Println("Remaining Correction: ", remainingCorrection)
-	fmt.Println("Border Length: ", borderLen)
-
-	fmt.Println("Corners: ", len(corners))
+	logging.Debug("Correction", "remaining", remainingCorrection)
+	logging.Debug("Border", "length", borderLen, "corners", len(corners))
 	trapezoid := 0
 	for i := 0; i < len(corners)-1; i++ {
 		trapezoid += corners[i].X*corners[i+1].Y - corners[i+1].X*corners[i].Y
 	}
 	trapezoid = abs(trapezoid / 2)
-	fmt.Println("Trapezoid: ", trapezoid)
+	logging.Debug("Trapezoid", "area", trapezoid)
 
-	return trapezoid + correctionCorners + remainingCorrection
+	return trapezoid + correctionCorners + remainingCorrection, nil
 }
 
 func main() {
+	flag.Parse()
+	logging.Setup("day18")
 	cmds, readErr := readData("input.txt")
-	// Cmd length
-	fmt.Println("Cmds length: ", len(cmds))
 	if readErr != nil {
 		fmt.Println("Error reading file: ", readErr)
 		return
 	}
-	fmt.Println("Part 1: ", solutionPart1(cmds))
+	logging.Debug("Read commands", "count", len(cmds))
+	part1, err := run.Answer(18, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, cmds)
+	})
+	if err != nil {
+		fmt.Println("Error: ", err)
+		return
+	}
+	fmt.Println("Part 1: ", part1)
 	cmds, readErr = readDataPart2("input.txt")
-	// Cmd length
-	fmt.Println("Cmds length: ", len(cmds))
 	if readErr != nil {
 		fmt.Println("Error reading file: ", readErr)
 		return
 	}
-	fmt.Println("Part 2: ", solutionPart1(cmds))
+	logging.Debug("Read commands", "count", len(cmds))
+	part2, err := run.Answer(18, 2, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, cmds)
+	})
+	if err != nil {
+		fmt.Println("Error: ", err)
+		return
+	}
+	fmt.Println("Part 2: ", part2)
 }
@@ -3,3 +3,7 @@ module day19
 go 1.23.3
 
 require github.com/tiendc/go-deepcopy v1.5.0 // indirect
+
+require aoc v0.0.0
+
+replace aoc => ../aoc
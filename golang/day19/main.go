@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"aoc/logging"
+	"aoc/run"
+	"aoc/shrink"
+
 	"github.com/tiendc/go-deepcopy"
 )
 
@@ -204,10 +210,13 @@ func readPart2(filepath string) map[string]*AltWorkflow {
 	return workflows
 }
 
-func solutionPart1(workflows map[string]*Workflow, parts []*Part) int {
+func solutionPart1(ctx context.Context, workflows map[string]*Workflow, parts []*Part) (int, error) {
 	accepted := make([]*Part, 0)
 	rejected := make([]*Part, 0)
 	for _, part := range parts {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		nextAction := "in"
 		for nextAction != "A" && nextAction != "R" {
 			nextAction = workflows[nextAction].nextAction(part)
@@ -219,14 +228,14 @@ func solutionPart1(workflows map[string]*Workflow, parts []*Part) int {
 		}
 	}
 
-	log.Printf("Accepted: %d, Rejected: %d\n", len(accepted), len(rejected))
+	logging.Debug("Sorted parts", "accepted", len(accepted), "rejected", len(rejected))
 
 	sumAccepted := 0
 	for _, part := range accepted {
 		sumAccepted += part.AddUp()
 	}
 
-	return sumAccepted
+	return sumAccepted, nil
 }
 
 type Status struct {
@@ -345,6 +354,11 @@ func ApplyRule(status *Status, rule AltRule) (*Status, *Status) {
 			return higherStatus, lowerStatus
 		}
 	}
+	// the whole interval is on one side of the rule
+	if rule.Operation == gt && intervall.Min > rule.Num || rule.Operation == lt && intervall.Max < rule.Num {
+		status.NextWorkflow = rule.NextWorkflow
+		return nil, status
+	}
 	return status, nil
 }
 
@@ -358,7 +372,7 @@ func NextIntervall(status []Status, workflows map[string]*AltWorkflow) []Status
 			if updated != nil && !updated.AnyEmpty() {
 				nextStatus = append(nextStatus, *updated)
 			}
-			if remain.AnyEmpty() {
+			if remain == nil || remain.AnyEmpty() {
 				currentStatus = nil
 				break
 			}
@@ -372,19 +386,28 @@ func NextIntervall(status []Status, workflows map[string]*AltWorkflow) []Status
 	return nextStatus
 }
 
-func calculateIntervalsForField(workflows map[string]*AltWorkflow) []Status {
+// maxRating is the highest rating of the puzzle, part 2 counts the
+// combinations of ratings from 1 to maxRating.
+const maxRating = 4000
+
+// calculateIntervalsForField returns the accepted ranges of ratings from 1
+// to bound.
+func calculateIntervalsForField(ctx context.Context, workflows map[string]*AltWorkflow, bound int) ([]Status, error) {
 	s := []Status{
 		{
-			X:            &Intervall{Min: 1, Max: 4000},
-			M:            &Intervall{Min: 1, Max: 4000},
-			A:            &Intervall{Min: 1, Max: 4000},
-			S:            &Intervall{Min: 1, Max: 4000},
+			X:            &Intervall{Min: 1, Max: bound},
+			M:            &Intervall{Min: 1, Max: bound},
+			A:            &Intervall{Min: 1, Max: bound},
+			S:            &Intervall{Min: 1, Max: bound},
 			NextWorkflow: "in",
 		},
 	}
 	finishedStatus := make([]Status, 0)
 	currentStatus := s
 	for len(currentStatus) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		currentStatus = NextIntervall(currentStatus, workflows)
 		nextStatus := make([]Status, 0)
 		for _, status := range currentStatus {
@@ -398,17 +421,20 @@ func calculateIntervalsForField(workflows map[string]*AltWorkflow) []Status {
 		}
 		currentStatus = nextStatus
 	}
-	return finishedStatus
+	return finishedStatus, nil
 }
 
-func solutionPart2(workflows map[string]*AltWorkflow) int {
-	finidshedIntevals := calculateIntervalsForField(workflows)
+func solutionPart2(ctx context.Context, workflows map[string]*AltWorkflow, bound int) (int, error) {
+	finidshedIntevals, err := calculateIntervalsForField(ctx, workflows, bound)
+	if err != nil {
+		return 0, err
+	}
 	for _, status := range finidshedIntevals {
 		for _, status2 := range finidshedIntevals {
 			if status != status2 {
 				intersect := status.Intersect(&status2)
 				if intersect != nil && !intersect.AnyEmpty() {
-					log.Println("Intersect", intersect)
+					logging.Debug("Accepted intervals intersect", "intersection", intersect)
 				}
 			}
 		}
@@ -417,11 +443,35 @@ func solutionPart2(workflows map[string]*AltWorkflow) int {
 	for _, status := range finidshedIntevals {
 		sum += status.Size()
 	}
-	return sum
+	return sum, nil
 }
 
+var reference = flag.Bool("reference", false, "cross-check against the brute-force reference solver")
+
 func main() {
+	flag.Parse()
+	logging.Setup("day19")
+	if shrink.Main(shrinker) {
+		return
+	}
+	if *reference {
+		runReference()
+		return
+	}
 	workflows, parts := readData("input.txt")
-	log.Println(solutionPart1(workflows, parts))
-	log.Println(solutionPart2(readPart2("input.txt")))
+	part1, err := run.Answer(19, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, workflows, parts)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(part1)
+	altWorkflows := readPart2("input.txt")
+	part2, err := run.Answer(19, 2, func(ctx context.Context) (int, error) {
+		return solutionPart2(ctx, altWorkflows, maxRating)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(part2)
 }
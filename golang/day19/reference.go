@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+)
+
+// referenceMaxRating bounds the ratings the reference solver tries, as it
+// runs every single part through the workflows. Rules comparing against
+// larger ratings then always go the same way.
+const referenceMaxRating = 30
+
+func isAccepted(workflows map[string]*Workflow, part *Part) bool {
+	nextAction := "in"
+	for nextAction != "A" && nextAction != "R" {
+		nextAction = workflows[nextAction].nextAction(part)
+	}
+	return nextAction == "A"
+}
+
+// referencePart2 counts the accepted parts with ratings from 1 to bound by
+// trying every one of them, which is only feasible for small bounds.
+func referencePart2(workflows map[string]*Workflow, bound int) int {
+	count := 0
+	for x := 1; x <= bound; x++ {
+		for m := 1; m <= bound; m++ {
+			for a := 1; a <= bound; a++ {
+				for s := 1; s <= bound; s++ {
+					if isAccepted(workflows, &Part{X: x, M: m, A: a, S: s}) {
+						count++
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
+func runReference() {
+	workflows, _ := readData("input.txt")
+	fast, err := solutionPart2(context.Background(), readPart2("input.txt"), referenceMaxRating)
+	if err != nil {
+		log.Fatal(err)
+	}
+	reference := referencePart2(workflows, referenceMaxRating)
+	log.Printf("Part 2 up to rating %d: %d, reference: %d\n", referenceMaxRating, fast, reference)
+	if fast != reference {
+		log.Fatal("fast solution differs from reference")
+	}
+}
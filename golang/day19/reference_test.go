@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const exampleWorkflows = `px{a<2006:qkq,m>2090:A,rfg}
+pv{a>1716:R,A}
+lnx{m>1548:A,A}
+rfg{s<537:gd,x>2440:R,A}
+qs{s>3448:A,lnx}
+qkq{x<1416:A,crn}
+crn{x>2662:A,R}
+in{s<1351:px,qqz}
+qqz{s>2770:qs,m<1801:hdj,R}
+gd{a>3333:R,R}
+hdj{m>838:A,pv}`
+
+const exampleParts = `{x=787,m=2655,a=1222,s=2876}
+{x=1679,m=44,a=2067,s=496}
+{x=2036,m=264,a=79,s=2244}
+{x=2461,m=1339,a=466,s=291}
+{x=2127,m=1623,a=2188,s=1013}`
+
+const example = exampleWorkflows + "\n\n" + exampleParts
+
+var referenceTests = []struct {
+	name      string
+	workflows string
+	bound     int
+}{
+	{"example", exampleWorkflows, 12},
+	{"thresholds at the bounds", `in{x>11:A,x<2:A,m>1:ab,R}
+ab{a<12:A,s>1:R,A}`, 12},
+	{"interval narrowed before the rule", `in{x<5:ab,x>8:cd,R}
+ab{x<7:A,R}
+cd{x>3:ef,R}
+ef{m<4:R,a>6:A,s<3:A,R}`, 12},
+	{"same field twice", `in{m>4:ab,m<3:ab,A}
+ab{m>6:R,m<2:R,m>3:A,s>5:A,R}`, 12},
+	{"thresholds above the bound", `in{x<100:ab,R}
+ab{m>50:R,a<9:A,R}`, 12},
+}
+
+func writeInput(t *testing.T, input string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPart2(t *testing.T) {
+	for _, test := range referenceTests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeInput(t, test.workflows+"\n\n{x=1,m=1,a=1,s=1}")
+			workflows, _ := readData(path)
+			got, err := solutionPart2(context.Background(), readPart2(path), test.bound)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := referencePart2(workflows, test.bound); got != want {
+				t.Errorf("got %d, reference %d", got, want)
+			}
+		})
+	}
+}
+
+func TestExampleAnswers(t *testing.T) {
+	path := writeInput(t, example)
+	workflows, parts := readData(path)
+	part1, err := solutionPart1(context.Background(), workflows, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != 19114 {
+		t.Errorf("part 1: got %d, want 19114", part1)
+	}
+	part2, err := solutionPart2(context.Background(), readPart2(path), maxRating)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part2 != 167409079868000 {
+		t.Errorf("part 2: got %d, want 167409079868000", part2)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+
+	"aoc/shrink"
+)
+
+var workflowNameRegex = regexp.MustCompile(`[a-z]{2,}`)
+
+var shrinker = shrink.Day{
+	Predicates: map[string]func() bool{
+		// reference: part 2 differs from the reference solver.
+		"reference": func() bool {
+			workflows, _ := readData("input.txt")
+			fast, err := solutionPart2(context.Background(), readPart2("input.txt"), referenceMaxRating)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return fast != referencePart2(workflows, referenceMaxRating)
+		},
+	},
+	// Anonymize renames the workflows except for in. Field names are single
+	// letters and not affected.
+	Anonymize: func(input string) string {
+		renamer := shrink.NewRenamer(func(id string, n int) string {
+			return shrink.Letters(n, 2, "abcdefghijklmnopqrstuvwxyz")
+		}, "in")
+		workflows, parts, _ := strings.Cut(input, "\n\n")
+		return renamer.ReplaceAll(workflowNameRegex, workflows) + "\n\n" + parts
+	},
+}
@@ -2,12 +2,17 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"flag"
 	"log"
 	"maps"
 	"os"
 	"reflect"
 	"slices"
 	"strings"
+
+	"aoc/logging"
+	"aoc/run"
 )
 
 type ModuleType string
@@ -172,7 +177,7 @@ func doCycle(modules map[string]Module, cycle int, mem map[string][]int) (int, i
 		processOutput := module.Process(pulse.Sender, pulse.high)
 		if pulse.Receiver == "hp" && (pulse.high || seen[pulse.Sender]) {
 			seen[pulse.Sender] = true
-			log.Println("Cycle:", cycle, "Sender:", pulse.Sender, "High:", pulse.high)
+			logging.Trace("Pulse to hp", "cycle", cycle, "sender", pulse.Sender, "high", pulse.high)
 			mem[pulse.Sender] = append(mem[pulse.Sender], cycle)
 		}
 		// If the output is -1, continue
@@ -248,12 +253,15 @@ func getPointerValue(i interface{}) uintptr {
 	return reflect.ValueOf(i).Pointer()
 }
 
-func solutionPart2(modules map[string]Module) int {
+func solutionPart2(ctx context.Context, modules map[string]Module) (int, error) {
 	highPulses := 0
 	lowPulses := 0
 	cycle := 1
 	mem := map[string][]int{}
 	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		high, low := doCycle(modules, cycle, mem)
 		highPulses += high
 		lowPulses += low
@@ -274,11 +282,19 @@ func solutionPart2(modules map[string]Module) int {
 	for _, v := range diffMap {
 		mult *= v
 	}
-	return mult
+	return mult, nil
 }
 
 func main() {
+	flag.Parse()
+	logging.Setup("day20")
 	modules := readData("input.txt")
 	//log.Printf("Solution part 1: %d", solutionPart1(modules))
-	log.Printf("Solution part 2: %d", solutionPart2(modules))
+	part2, err := run.Answer(20, 2, func(ctx context.Context) (int, error) {
+		return solutionPart2(ctx, modules)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Solution part 2: %d", part2)
 }
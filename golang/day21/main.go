@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"image/color"
 	"log"
 	"os"
 	"slices"
 	"strings"
 	"time"
+
+	"aoc/animation"
+	"aoc/logging"
+	"aoc/render"
+	"aoc/run"
 )
 
 func positiveModulo(a, b int) int {
@@ -76,15 +84,27 @@ func readData(filepath string) (Grid, Point) {
 	grid.Width = len(grid.Cells[0])
 	grid.Height = len(grid.Cells)
 	grid.Offset = grid.Width / 2
-	fmt.Println("Width: ", grid.Width)
-	fmt.Println("Height: ", grid.Height)
-	fmt.Println("Offset: ", grid.Offset)
+	logging.Debug("Read grid", "width", grid.Width, "height", grid.Height, "offset", grid.Offset)
 	return grid, Point{0, 0}
 }
 
-func solutionPart1(grid Grid, start Point) {
+var recorder *animation.Recorder
+
+func (g *Grid) picture(points map[Point]bool) *render.Picture {
+	frontier := make([]render.Point, 0, len(points))
+	for point := range points {
+		frontier = append(frontier, render.Point{X: point.X + g.Offset, Y: point.Y + g.Offset})
+	}
+	return render.New(render.Runes(g.Cells), render.Highlight{Points: frontier, Color: color.RGBA{80, 200, 120, 255}})
+}
+
+func solutionPart1(ctx context.Context, grid Grid, start Point) error {
 	currentPoints := map[Point]bool{start: true}
+	recorder.Frame(func() *render.Picture { return grid.picture(currentPoints) })
 	for i := 0; i < 64; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		nextPoints := map[Point]bool{}
 		for point := range currentPoints {
 			for _, neighbour := range grid.getValidNeighbours(point) {
@@ -92,8 +112,10 @@ func solutionPart1(grid Grid, start Point) {
 			}
 		}
 		currentPoints = nextPoints
+		recorder.Frame(func() *render.Picture { return grid.picture(currentPoints) })
 	}
 	fmt.Println(len(currentPoints))
+	return nil
 }
 
 func (g *Grid) countPointsInGridWithOffset(offset Point, points map[Point]int) int {
@@ -121,9 +143,16 @@ func PowInts(x, n int) int {
 	return x * y * y
 }
 
-func solutionPart2(grid Grid, start Point) {
+// countReachablePart2 extrapolates the number of garden plots reachable in
+// exactly n*Width+Offset steps from the tile counts of a small simulation.
+// It relies on the clear start row and column of the puzzle input and on n
+// being even.
+func countReachablePart2(ctx context.Context, grid Grid, start Point, n int) (int, error) {
 	currentPoints := map[Point]int{start: 0}
 	for i := 0; i < grid.Height*2+grid.Offset; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		nextPoints := map[Point]int{}
 		for point, val := range currentPoints {
 			for _, neighbour := range grid.getValidNeighboursPart2(point) {
@@ -135,10 +164,9 @@ func solutionPart2(grid Grid, start Point) {
 	}
 
 	countOdd := 0
-	fmt.Println("(0,0)", grid.countPointsInGridWithOffset(Point{0, 0}, currentPoints))
-	fmt.Println("(1,0)", grid.countPointsInGridWithOffset(Point{1, 0}, currentPoints))
 	fullOdd := grid.countPointsInGridWithOffset(Point{0, 0}, currentPoints)
 	fullEven := grid.countPointsInGridWithOffset(Point{1, 0}, currentPoints)
+	logging.Debug("Full tiles", "odd", fullOdd, "even", fullEven)
 	oddEdgePoints := []Point{
 		{-1, 1},
 		{-1, -1},
@@ -146,10 +174,11 @@ func solutionPart2(grid Grid, start Point) {
 		{1, 1},
 	}
 	for _, point := range oddEdgePoints {
-		// fmt.Println(point, (fullOdd - grid.countPointsInGridWithOffset(point, currentPoints)))
-		countOdd += (fullOdd - grid.countPointsInGridWithOffset(point, currentPoints))
+		missing := fullOdd - grid.countPointsInGridWithOffset(point, currentPoints)
+		logging.Trace("Odd edge tile", "tile", point, "missing", missing)
+		countOdd += missing
 	}
-	fmt.Println("Count Odd: ", countOdd)
+	logging.Debug("Odd edge tiles", "count", countOdd)
 
 	countEven := 0
 	evenEdgePoints := []Point{
@@ -159,21 +188,51 @@ func solutionPart2(grid Grid, start Point) {
 		{-2, 1},
 	}
 	for _, point := range evenEdgePoints {
-		// fmt.Println(point, grid.countPointsInGridWithOffset(point, currentPoints))
-		countEven += grid.countPointsInGridWithOffset(point, currentPoints)
+		count := grid.countPointsInGridWithOffset(point, currentPoints)
+		logging.Trace("Even edge tile", "tile", point, "count", count)
+		countEven += count
 	}
+	logging.Debug("Even edge tiles", "count", countEven)
 
-	n := 202300
-	//n := 2
-	log.Println("Points: ", PowInts(n, 2)*fullEven+PowInts(n+1, 2)*fullOdd-(n+1)*countOdd+n*countEven)
+	return PowInts(n, 2)*fullEven + PowInts(n+1, 2)*fullOdd - (n+1)*countOdd + n*countEven, nil
+}
 
+func solutionPart2(ctx context.Context, grid Grid, start Point) error {
+	points, err := countReachablePart2(ctx, grid, start, 202300)
+	if err != nil {
+		return err
+	}
+	log.Println("Points: ", points)
+	return nil
 }
 
+var reference = flag.Bool("reference", false, "cross-check against the brute-force reference solver")
+
 func main() {
+	flag.Parse()
+	logging.Setup("day21")
+	recorder = animation.FromFlags()
+	if *reference {
+		runReference()
+		return
+	}
 	grid, start := readData("input.txt")
-	solutionPart1(grid, start)
+	err := run.Part(21, 1, func(ctx context.Context) error {
+		return solutionPart1(ctx, grid, start)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 	grid, start = readData("input.txt")
 	startTime := time.Now()
-	solutionPart2(grid, start)
-	log.Println(time.Since(startTime))
+	err = run.Part(21, 2, func(ctx context.Context) error {
+		return solutionPart2(ctx, grid, start)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.Debug("Part 2 done", "duration", time.Since(startTime))
+	if err := recorder.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
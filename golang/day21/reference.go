@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+)
+
+// referenceReachable walks the infinite garden step by step and counts the
+// plots reached after exactly steps steps.
+func referenceReachable(grid Grid, start Point, steps int) int {
+	currentPoints := map[Point]bool{start: true}
+	for i := 0; i < steps; i++ {
+		nextPoints := map[Point]bool{}
+		for point := range currentPoints {
+			for _, neighbour := range grid.getValidNeighboursPart2(point) {
+				nextPoints[neighbour] = true
+			}
+		}
+		currentPoints = nextPoints
+	}
+	return len(currentPoints)
+}
+
+func runReference() {
+	grid, start := readData("input.txt")
+	mismatch := false
+	for _, n := range []int{2, 4} {
+		fast, err := countReachablePart2(context.Background(), grid, start, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reference := referenceReachable(grid, start, n*grid.Width+grid.Offset)
+		log.Printf("n = %d: %d, reference: %d\n", n, fast, reference)
+		mismatch = mismatch || fast != reference
+	}
+	if mismatch {
+		log.Fatal("fast solution differs from reference")
+	}
+}
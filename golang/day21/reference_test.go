@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The gardens keep the border, the start row and the start column clear, as
+// countReachablePart2 expects.
+var gardens = []struct {
+	name   string
+	garden string
+}{
+	{"empty", `.........
+.........
+.........
+.........
+....S....
+.........
+.........
+.........
+.........`},
+	{"unreachable plot", `.........
+.###.....
+.#.#.....
+.###.....
+....S....
+.........
+.....##..
+.....#...
+.........`},
+	{"rocks", `...........
+.#.........
+.##....#...
+....#.#....
+...#.......
+.....S.....
+....#....#.
+...#.......
+...........
+.#....#....
+...........`},
+	{"more rocks", `.............
+...##........
+..#..........
+.##........#.
+..#....#.....
+.......#.....
+......S......
+.............
+.......#.....
+.............
+..#....#.#...
+...#.........
+.............`},
+}
+
+func TestCountReachablePart2(t *testing.T) {
+	for _, test := range gardens {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "input.txt")
+			if err := os.WriteFile(path, []byte(test.garden), 0644); err != nil {
+				t.Fatal(err)
+			}
+			grid, start := readData(path)
+			for _, n := range []int{2, 4, 6} {
+				got, err := countReachablePart2(context.Background(), grid, start, n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := referenceReachable(grid, start, n*grid.Width+grid.Offset); got != want {
+					t.Errorf("n = %d: got %d, reference %d", n, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"image/color"
 	"log"
 	"maps"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+
+	"aoc/animation"
+	"aoc/logging"
+	"aoc/render"
+	"aoc/run"
 )
 
 type Point struct {
@@ -193,14 +201,49 @@ func calculateFallingBricks(supportMap map[int][]int, invertedSupportMap map[int
 	return len(fallingsBricks) - 1
 }
 
-func common(bricks []*Brick) map[int][]int {
+var recorder *animation.Recorder
+
+func brickColor(idx int) color.Color {
+	return color.RGBA{uint8(55 + idx*67%200), uint8(55 + idx*131%200), uint8(55 + idx*29%200), 255}
+}
+
+// sideView projects the bricks onto the x-z plane with the ground at the
+// bottom. Bricks with a smaller y are in front.
+func sideView(bricks []*Brick, width, height int) *render.Picture {
+	order := make([]int, len(bricks))
+	for idx := range order {
+		order[idx] = idx
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return bricks[b].Start.Y - bricks[a].Start.Y
+	})
+	picture := render.New(render.Blank{Width: width, Height: height, Fill: '.'})
+	for _, idx := range order {
+		points := []render.Point{}
+		for _, point := range bricks[idx].Points {
+			points = append(points, render.Point{X: point.X, Y: height - 1 - point.Z})
+		}
+		picture.Add(render.Highlight{Points: points, Color: brickColor(idx)})
+	}
+	return picture
+}
+
+func common(ctx context.Context, bricks []*Brick, rec *animation.Recorder) (map[int][]int, error) {
 	// sort bricks by z coordinate
 	slices.SortFunc(bricks, func(a, b *Brick) int {
 		return a.Start.Z - b.Start.Z
 	})
+	width, height := 0, 0
+	for _, brick := range bricks {
+		width = max(width, brick.End.X+1, brick.Start.X+1)
+		height = max(height, brick.End.Z+1)
+	}
 	occupied := map[Point]int{}
 	// let bricks fall down as far as possible
 	for idx, brick := range bricks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		for brick.Start.Z > 1 && !checkForIntersection(brick, occupied) {
 			brick.FallDown()
 		}
@@ -211,38 +254,65 @@ func common(bricks []*Brick) map[int][]int {
 			}
 			occupied[p] = idx
 		}
+		rec.Frame(func() *render.Picture { return sideView(bricks, width, height) })
 	}
 
-	return buildSupportMap(bricks, occupied)
+	return buildSupportMap(bricks, occupied), nil
 }
 
-func solutionPart1(bricks []*Brick) int {
-	supportMap := common(bricks)
+func solutionPart1(ctx context.Context, bricks []*Brick) (int, error) {
+	supportMap, err := common(ctx, bricks, recorder)
+	if err != nil {
+		return 0, err
+	}
 	bricksNeccessary := make(map[int]bool)
 	for idx := range bricks {
 		if len(supportMap[idx]) == 1 {
 			bricksNeccessary[supportMap[idx][0]] = true
 		}
 	}
-	log.Println(len(bricksNeccessary))
-	log.Println(len(bricks))
-	return len(bricks) - len(bricksNeccessary)
+	logging.Debug("Counted bricks", "necessary", len(bricksNeccessary), "total", len(bricks))
+	return len(bricks) - len(bricksNeccessary), nil
 }
 
-func solutionPart2(bricks []*Brick) int {
-	supportMap := common(bricks)
+func solutionPart2(ctx context.Context, bricks []*Brick) (int, error) {
+	supportMap, err := common(ctx, bricks, nil)
+	if err != nil {
+		return 0, err
+	}
 	invertedSupportMap := invertSupportMap(supportMap)
 
 	count := 0
 	for idx := range bricks {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		count += calculateFallingBricks(supportMap, invertedSupportMap, idx)
 	}
-	return count
+	return count, nil
 }
 
 func main() {
+	flag.Parse()
+	logging.Setup("day22")
+	recorder = animation.FromFlags()
 	bricks := readData("input.txt")
-	log.Println(solutionPart1(bricks))
+	part1, err := run.Answer(22, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, bricks)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(part1)
 	bricks = readData("example.txt")
-	log.Println(solutionPart2(bricks))
+	part2, err := run.Answer(22, 2, func(ctx context.Context) (int, error) {
+		return solutionPart2(ctx, bricks)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(part2)
+	if err := recorder.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -3,11 +3,18 @@ package main
 import (
 	"bytes"
 	"container/list"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"maps"
 	"os"
 	"slices"
 	"time"
+
+	"aoc/logging"
+	"aoc/render"
+	"aoc/run"
 )
 
 type Point struct {
@@ -63,24 +70,15 @@ func (grid Grid) ValidNeighboursP2(p Point) []Point {
 }
 
 func (grid Grid) String() string {
-	var buffer bytes.Buffer
-	for _, row := range grid.data {
-		buffer.WriteString(string(row) + "\n")
-	}
-	return buffer.String()
+	return render.New(render.Runes(grid.data)).Text()
 }
 
 func (grid Grid) PrintPath(path map[Point]struct{}) {
-	for y, row := range grid.data {
-		for x, cell := range row {
-			if _, ok := path[Point{x, y}]; ok {
-				fmt.Print("O")
-			} else {
-				fmt.Print(string(cell))
-			}
-		}
-		fmt.Println()
+	points := make([]render.Point, 0, len(path))
+	for point := range path {
+		points = append(points, render.Point{X: point.X, Y: point.Y})
 	}
+	fmt.Print(render.New(render.Runes(grid.data), render.Path{Points: points, Rune: 'O'}).Text())
 }
 
 func readData(filepath string) Grid {
@@ -110,11 +108,14 @@ func (qi QueueItem) Copy() QueueItem {
 	return QueueItem{qi.Point, newPath}
 }
 
-func solutionPart1(data Grid) int {
+func solutionPart1(ctx context.Context, data Grid) (int, error) {
 	// fmt.Printf("%v\n", data)
 	queue := []QueueItem{{Point{1, 0}, map[Point]struct{}{{1, 0}: {}}}}
 	weightMap := map[Point]int{{1, 0}: 0}
 	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		current := queue[0]
 		queue = queue[1:]
 		neighbours := data.ValidNeighbours(current.Point)
@@ -140,16 +141,19 @@ func solutionPart1(data Grid) int {
 			}
 		}
 	}
-	return weightMap[Point{data.width - 2, data.height - 1}]
+	return weightMap[Point{data.width - 2, data.height - 1}], nil
 }
 
-func solutionPart2(data Grid) int {
+func solutionPart2(ctx context.Context, data Grid) (int, error) {
 	// fmt.Printf("%v\n", data)
 	queue := []QueueItem{{Point{1, 0}, map[Point]struct{}{{1, 0}: {}}}}
 	weightMap := map[Point]int{{1, 0}: 0}
 	end := Point{data.width - 2, data.height - 1}
 
 	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		current := queue[0]
 		queue = queue[1:]
 
@@ -177,7 +181,7 @@ func solutionPart2(data Grid) int {
 		}
 	}
 
-	return weightMap[end]
+	return weightMap[end], nil
 }
 
 type PointDistance struct {
@@ -186,7 +190,7 @@ type PointDistance struct {
 }
 
 func buildGraph(grid Grid, start Point, end Point) map[Point][]PointDistance {
-	fmt.Println(start, end)
+	logging.Debug("Building graph", "start", start, "end", end)
 	graph := map[Point][]PointDistance{}
 	queue := []Point{start}
 	visited := map[Point]bool{}
@@ -231,9 +235,12 @@ func buildGraph(grid Grid, start Point, end Point) map[Point][]PointDistance {
 	return graph
 }
 
-func dfsLongestPath(graph map[Point][]PointDistance, current, goal Point, visited map[Point]bool) int {
+func dfsLongestPath(ctx context.Context, graph map[Point][]PointDistance, current, goal Point, visited map[Point]bool) (int, error) {
 	if current == goal {
-		return 0
+		return 0, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
 	}
 	visited[current] = true
 	maxLen := -1
@@ -241,7 +248,10 @@ func dfsLongestPath(graph map[Point][]PointDistance, current, goal Point, visite
 		if visited[next.Point] {
 			continue
 		}
-		subPath := dfsLongestPath(graph, next.Point, goal, visited)
+		subPath, err := dfsLongestPath(ctx, graph, next.Point, goal, visited)
+		if err != nil {
+			return 0, err
+		}
 		if subPath >= 0 {
 			total := next.Distance + subPath
 			if total > maxLen {
@@ -250,20 +260,23 @@ func dfsLongestPath(graph map[Point][]PointDistance, current, goal Point, visite
 		}
 	}
 	visited[current] = false
-	return maxLen
+	return maxLen, nil
 }
 
 type setBackIndicator struct {
 	point Point
 }
 
-func dfsStack(graph map[Point][]PointDistance, end Point) int {
+func dfsStack(ctx context.Context, graph map[Point][]PointDistance, end Point) (int, error) {
 	stack := list.New()
 	visited := map[Point]bool{}
 	start := PointDistance{Point{1, 0}, 0}
 	stack.PushBack(start)
 	maxLen := -1
 	for stack.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		last := stack.Back()
 		stack.Remove(last)
 		var current PointDistance
@@ -289,17 +302,38 @@ func dfsStack(graph map[Point][]PointDistance, end Point) int {
 			stack.PushBack(PointDistance{next.Point, next.Distance + current.Distance})
 		}
 	}
-	return maxLen
+	return maxLen, nil
 }
 
 func main() {
+	flag.Parse()
+	logging.Setup("day23")
 	start := time.Now()
 	data := readData("input.txt")
-	println(solutionPart1(data))
-	graph := buildGraph(data, Point{1, 0}, Point{data.width - 2, data.height - 1})
-	visited := map[Point]bool{}
-	println(dfsLongestPath(graph, Point{1, 0}, Point{data.width - 2, data.height - 1}, visited))
-	fmt.Println("----------")
-	println(dfsStack(graph, Point{data.width - 2, data.height - 1}))
-	fmt.Printf("Execution time: %v\n", time.Since(start))
+	part1, err := run.Answer(23, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, data)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	println(part1)
+	end := Point{data.width - 2, data.height - 1}
+	graph := buildGraph(data, Point{1, 0}, end)
+	part2, err := run.Answer(23, 2, func(ctx context.Context) (int, error) {
+		visited := map[Point]bool{}
+		return dfsLongestPath(ctx, graph, Point{1, 0}, end, visited)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	println(part2)
+	logging.Debug("Solving part 2 again with an explicit stack")
+	part2, err = run.Answer(23, 2, func(ctx context.Context) (int, error) {
+		return dfsStack(ctx, graph, end)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	println(part2)
+	logging.Debug("Done", "duration", time.Since(start))
 }
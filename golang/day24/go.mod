@@ -3,3 +3,7 @@ module day24
 go 1.23.3
 
 require gonum.org/v1/gonum v0.16.0 // indirect
+
+require aoc v0.0.0
+
+replace aoc => ../aoc
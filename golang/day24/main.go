@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"math"
 	"os"
 	"strconv"
 	"strings"
 
+	"aoc/run"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -90,9 +94,12 @@ func intersectionPoint(line1, line2 *Line2D) (float64, float64, float64, float64
 	return float64(line1.FixPoint.X) + s/det*float64(line1.Direction.X), float64(line1.FixPoint.Y) + s/det*float64(line1.Direction.Y), s / det, t / det
 }
 
-func solutionPart1(lines []*Line3D, minValue float64, maxValue float64) int {
+func solutionPart1(ctx context.Context, lines []*Line3D, minValue float64, maxValue float64) (int, error) {
 	count := 0
 	for i, line1 := range lines {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		for j := i + 1; j < len(lines); j++ {
 			line2 := lines[j]
 			if hasIntersection(Line2DFrom3D(line1), Line2DFrom3D(line2)) {
@@ -103,10 +110,10 @@ func solutionPart1(lines []*Line3D, minValue float64, maxValue float64) int {
 			}
 		}
 	}
-	return count
+	return count, nil
 }
 
-func solutionPart2(lines []*Line3D) int {
+func solutionPart2(ctx context.Context, lines []*Line3D) (int, error) {
 	indices := [2][2]int{{0, 1}, {0, 2}}
 	data := []float64{}
 	rhs := []float64{}
@@ -157,14 +164,29 @@ func solutionPart2(lines []*Line3D) int {
 	if sum < 0 {
 		sum = -sum
 	}
-	return sum
+	return sum, nil
+}
+
+func solve(filepath string, minValue float64, maxValue float64) {
+	lines := readData(filepath)
+	part1, err := run.Answer(24, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, lines, minValue, maxValue)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Solution Part 1:", part1)
+	part2, err := run.Answer(24, 2, func(ctx context.Context) (int, error) {
+		return solutionPart2(ctx, lines)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Solution Part 2:", part2)
 }
 
 func main() {
-	lines := readData("example.txt")
-	log.Println("Solution Part 1:", solutionPart1(lines, 7, 27))
-	log.Println("Solution Part 2:", solutionPart2(lines))
-	lines = readData("input.txt")
-	log.Println("Solution Part 1:", solutionPart1(lines, 200000000000000, 400000000000000))
-	log.Println("Solution Part 2:", solutionPart2(lines))
+	flag.Parse()
+	solve("example.txt", 7, 27)
+	solve("input.txt", 200000000000000, 400000000000000)
 }
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
 	"math"
 	"os"
 	"strings"
 
 	"slices"
+
+	"aoc/run"
+	"aoc/shrink"
 )
 
 // Graph represents an undirected flow network.
@@ -174,8 +180,11 @@ func countReachable(reachable []bool) int {
 	return count
 }
 
-func findThreeCut(graph *Graph) []bool {
+func findThreeCut(ctx context.Context, graph *Graph) ([]bool, error) {
 	for i := 1; i < graph.N; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		graphCopy := CopyGraph(graph)
 		edmondsKarp(graphCopy, 0, i)
 
@@ -193,25 +202,38 @@ func findThreeCut(graph *Graph) []bool {
 			}
 		}
 		if countCut == 3 {
-			return reachable
+			return reachable, nil
 		}
 	}
-	return nil
+	return nil, nil
 }
 
-func solutionPart1(graph *Graph) int {
-	reachable := findThreeCut(graph)
+func solutionPart1(ctx context.Context, graph *Graph) (int, error) {
+	reachable, err := findThreeCut(ctx, graph)
+	if err != nil {
+		return 0, err
+	}
 	if reachable == nil {
-		return -1
+		return -1, nil
 	}
 
-	return countReachable(reachable) * (graph.N - countReachable(reachable))
+	return countReachable(reachable) * (graph.N - countReachable(reachable)), nil
 }
 
 func main() {
+	flag.Parse()
+	if shrink.Main(shrinker) {
+		return
+	}
 	graph, err := readData("input.txt")
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
+	}
+	part1, err := run.Answer(25, 1, func(ctx context.Context) (int, error) {
+		return solutionPart1(ctx, graph)
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
-	println(solutionPart1(graph))
+	println(part1)
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+
+	"aoc/shrink"
+)
+
+var componentRegex = regexp.MustCompile(`[a-z]+`)
+
+var shrinker = shrink.Day{
+	Predicates: map[string]func() bool{
+		// no-cut: no three edges separate the graph.
+		"no-cut": func() bool {
+			graph, err := readData("input.txt")
+			if err != nil {
+				log.Fatal(err)
+			}
+			reachable, err := findThreeCut(context.Background(), graph)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return reachable == nil
+		},
+	},
+	// Reduce removes lines first and then connections within the lines.
+	Reduce: func(input string, fails func(string) bool) string {
+		lines := strings.Split(shrink.Lines(input, fails), "\n")
+		for i, line := range lines {
+			component, connections, ok := strings.Cut(line, ": ")
+			if !ok {
+				continue
+			}
+			reduced := shrink.Slice(strings.Split(connections, " "), func(connections []string) bool {
+				lines[i] = component + ": " + strings.Join(connections, " ")
+				return fails(strings.Join(lines, "\n"))
+			})
+			lines[i] = component + ": " + strings.Join(reduced, " ")
+		}
+		return strings.Join(lines, "\n")
+	},
+	Anonymize: func(input string) string {
+		renamer := shrink.NewRenamer(func(id string, n int) string {
+			return shrink.Letters(n, 3, "abcdefghijklmnopqrstuvwxyz")
+		})
+		return renamer.ReplaceAll(componentRegex, input)
+	},
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type Star struct {
+	GetStarTs int64 `json:"get_star_ts"`
+	StarIndex int64 `json:"star_index"`
+}
+
+type Member struct {
+	ID                 int                        `json:"id"`
+	Name               *string                    `json:"name"`
+	Stars              int                        `json:"stars"`
+	LocalScore         int                        `json:"local_score"`
+	LastStarTs         int64                      `json:"last_star_ts"`
+	CompletionDayLevel map[string]map[string]Star `json:"completion_day_level"`
+}
+
+func (m *Member) DisplayName() string {
+	if m.Name == nil || *m.Name == "" {
+		return fmt.Sprintf("(anonymous user #%d)", m.ID)
+	}
+	return *m.Name
+}
+
+// StarTime returns the time the member got the star for the given day and
+// part, and false if the star has not been collected.
+func (m *Member) StarTime(day, part int) (time.Time, bool) {
+	star, ok := m.CompletionDayLevel[strconv.Itoa(day)][strconv.Itoa(part)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(star.GetStarTs, 0), true
+}
+
+type Leaderboard struct {
+	OwnerID int                `json:"owner_id"`
+	Event   string             `json:"event"`
+	Members map[string]*Member `json:"members"`
+}
+
+// SortedMembers returns the members ordered by their id, so output is stable.
+func (l *Leaderboard) SortedMembers() []*Member {
+	members := make([]*Member, 0, len(l.Members))
+	for _, member := range l.Members {
+		members = append(members, member)
+	}
+	slices.SortFunc(members, func(a, b *Member) int {
+		return a.ID - b.ID
+	})
+	return members
+}
+
+// Unlock returns the time the puzzle of the given day was released, which is
+// midnight in UTC-5.
+func (l *Leaderboard) Unlock(day int) time.Time {
+	year, err := strconv.Atoi(l.Event)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return time.Date(year, time.December, day, 5, 0, 0, 0, time.UTC)
+}
+
+func parseLeaderboard(content []byte) *Leaderboard {
+	leaderboard := &Leaderboard{}
+	if err := json.Unmarshal(content, leaderboard); err != nil {
+		log.Fatal(err)
+	}
+	for _, member := range leaderboard.Members {
+		for day := range member.CompletionDayLevel {
+			if num, err := strconv.Atoi(day); err != nil || num < 1 || num > 25 {
+				log.Fatal("Invalid day ", day, " for member ", member.DisplayName())
+			}
+		}
+	}
+	return leaderboard
+}
+
+func readData(filepath string) *Leaderboard {
+	fileContent, readErr := os.ReadFile(filepath)
+	if readErr != nil {
+		log.Fatal(readErr)
+	}
+	return parseLeaderboard(fileContent)
+}
+
+func fetchData(url, session string) *Leaderboard {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if session != "" {
+		request.AddCookie(&http.Cookie{Name: "session", Value: session})
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		log.Fatal("Fetching leaderboard failed: ", response.Status)
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return parseLeaderboard(content)
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDuration(-d)
+	}
+	seconds := int(d.Seconds())
+	days := seconds / 86400
+	result := fmt.Sprintf("%02d:%02d:%02d", seconds/3600%24, seconds/60%60, seconds%60)
+	if days > 0 {
+		result = fmt.Sprintf("%dd %s", days, result)
+	}
+	return result
+}
+
+func printStarTimes(leaderboard *Leaderboard) {
+	for _, member := range leaderboard.SortedMembers() {
+		fmt.Printf("%s (%d stars)\n", member.DisplayName(), member.Stars)
+		for day := 1; day <= 25; day++ {
+			part1, ok1 := member.StarTime(day, 1)
+			if !ok1 {
+				continue
+			}
+			unlock := leaderboard.Unlock(day)
+			line := fmt.Sprintf("  Day %2d: part 1 %12s", day, formatDuration(part1.Sub(unlock)))
+			if part2, ok2 := member.StarTime(day, 2); ok2 {
+				line += fmt.Sprintf(", part 2 %12s, delta %12s",
+					formatDuration(part2.Sub(unlock)), formatDuration(part2.Sub(part1)))
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// dayScores awards every star of the day and part the number of members minus
+// the number of members who were faster, the way the local score is defined.
+func dayScores(leaderboard *Leaderboard, day int) map[int]int {
+	scores := make(map[int]int)
+	for part := 1; part <= 2; part++ {
+		finishers := make([]*Member, 0)
+		for _, member := range leaderboard.Members {
+			if _, ok := member.StarTime(day, part); ok {
+				finishers = append(finishers, member)
+			}
+		}
+		slices.SortFunc(finishers, func(a, b *Member) int {
+			timeA, _ := a.StarTime(day, part)
+			timeB, _ := b.StarTime(day, part)
+			if c := timeA.Compare(timeB); c != 0 {
+				return c
+			}
+			return a.ID - b.ID
+		})
+		for rank, member := range finishers {
+			scores[member.ID] += len(leaderboard.Members) - rank
+		}
+	}
+	return scores
+}
+
+// ranks orders the members by score, best first, and returns the rank of
+// every member id. Equal scores share a rank.
+func ranks(members []*Member, scores map[int]int) map[int]int {
+	sorted := slices.Clone(members)
+	slices.SortStableFunc(sorted, func(a, b *Member) int {
+		return scores[b.ID] - scores[a.ID]
+	})
+	result := make(map[int]int)
+	for i, member := range sorted {
+		if i > 0 && scores[member.ID] == scores[sorted[i-1].ID] {
+			result[member.ID] = result[sorted[i-1].ID]
+		} else {
+			result[member.ID] = i + 1
+		}
+	}
+	return result
+}
+
+func printRankChanges(leaderboard *Leaderboard) map[int]int {
+	members := leaderboard.SortedMembers()
+	totals := make(map[int]int)
+	previousRanks := ranks(members, totals)
+	for day := 1; day <= 25; day++ {
+		scores := dayScores(leaderboard, day)
+		if len(scores) == 0 {
+			continue
+		}
+		for id, score := range scores {
+			totals[id] += score
+		}
+		currentRanks := ranks(members, totals)
+		fmt.Printf("Day %d\n", day)
+		slices.SortStableFunc(members, func(a, b *Member) int {
+			return currentRanks[a.ID] - currentRanks[b.ID]
+		})
+		for _, member := range members {
+			change := previousRanks[member.ID] - currentRanks[member.ID]
+			fmt.Printf("  %3d. %-30s %5d (+%d) %+d\n",
+				currentRanks[member.ID], member.DisplayName(), totals[member.ID], scores[member.ID], change)
+		}
+		previousRanks = currentRanks
+	}
+	return totals
+}
+
+func printLocalScores(leaderboard *Leaderboard, totals map[int]int) {
+	members := leaderboard.SortedMembers()
+	slices.SortStableFunc(members, func(a, b *Member) int {
+		return totals[b.ID] - totals[a.ID]
+	})
+	fmt.Println("Local scores")
+	for _, member := range members {
+		line := fmt.Sprintf("  %-30s %5d", member.DisplayName(), totals[member.ID])
+		if totals[member.ID] != member.LocalScore {
+			line += fmt.Sprintf(" (exported %d)", member.LocalScore)
+		}
+		fmt.Println(line)
+	}
+}
+
+func main() {
+	url := flag.String("url", "", "fetch the leaderboard JSON from this url instead of a file")
+	session := flag.String("session", os.Getenv("AOC_SESSION"), "session cookie used when fetching")
+	flag.Parse()
+
+	var leaderboard *Leaderboard
+	if *url != "" {
+		leaderboard = fetchData(*url, *session)
+	} else {
+		filepath := "leaderboard.json"
+		if flag.NArg() > 0 {
+			filepath = flag.Arg(0)
+		}
+		leaderboard = readData(filepath)
+	}
+
+	printStarTimes(leaderboard)
+	fmt.Println()
+	totals := printRankChanges(leaderboard)
+	fmt.Println()
+	printLocalScores(leaderboard, totals)
+}